@@ -0,0 +1,114 @@
+package app
+
+import (
+	"container/list"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/tomocy/smoothie/domain"
+)
+
+func newDedup(size int) *dedup {
+	return &dedup{
+		size:  size,
+		keys:  list.New(),
+		seens: make(map[string]*list.Element),
+	}
+}
+
+type dedup struct {
+	size  int
+	keys  *list.List
+	seens map[string]*list.Element
+}
+
+func (d *dedup) PostsOf(drv Driver, ps domain.Posts) domain.Posts {
+	deduped := make(domain.Posts, 0, len(ps))
+	for _, p := range ps {
+		if d.see(fmt.Sprintf("%s:%s", drv, p.ID)) {
+			continue
+		}
+
+		deduped = append(deduped, p)
+	}
+
+	return deduped
+}
+
+func (d *dedup) PostsByURL(ps domain.Posts) domain.Posts {
+	deduped := make(domain.Posts, 0, len(ps))
+	byURL := make(map[string]*domain.Post)
+	for _, p := range ps {
+		canonical := canonicalURL(linkOf(p))
+		if canonical == "" {
+			deduped = append(deduped, p)
+			continue
+		}
+		if collapsed, ok := byURL[canonical]; ok {
+			collapsed.Sources = append(collapsed.Sources, sourceOf(p))
+			continue
+		}
+		if d.see("url:" + canonical) {
+			continue
+		}
+
+		collapsed := *p
+		collapsed.Sources = []*domain.Source{sourceOf(p)}
+		byURL[canonical] = &collapsed
+		deduped = append(deduped, &collapsed)
+	}
+
+	return deduped
+}
+
+func (d *dedup) see(key string) bool {
+	if e, ok := d.seens[key]; ok {
+		d.keys.MoveToFront(e)
+		return true
+	}
+
+	d.seens[key] = d.keys.PushFront(key)
+	if 0 < d.size && d.size < d.keys.Len() {
+		oldest := d.keys.Back()
+		d.keys.Remove(oldest)
+		delete(d.seens, oldest.Value.(string))
+	}
+
+	return false
+}
+
+func sourceOf(p *domain.Post) *domain.Source {
+	return &domain.Source{
+		Driver: p.Driver, ID: p.ID, URL: p.URL,
+	}
+}
+
+func linkOf(p *domain.Post) string {
+	if p.Link != "" {
+		return p.Link
+	}
+
+	return p.URL
+}
+
+func canonicalURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	q := parsed.Query()
+	for k := range q {
+		if strings.HasPrefix(k, "utm_") {
+			q.Del(k)
+		}
+	}
+	canonical := host + strings.TrimSuffix(parsed.Path, "/")
+	if encoded := q.Encode(); encoded != "" {
+		canonical += "?" + encoded
+	}
+
+	return canonical
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tomocy/smoothie/domain"
+	"github.com/tomocy/smoothie/infra/hackernews"
+	"github.com/tomocy/smoothie/infra/reddit"
+	"github.com/tomocy/smoothie/infra/twitter"
+)
+
+func TestDedupPosts(t *testing.T) {
+	d := newDedup(2)
+	a1, a2, a3 := &domain.Post{ID: "1", Driver: "a"}, &domain.Post{ID: "2", Driver: "a"}, &domain.Post{ID: "3", Driver: "a"}
+	tests := []struct {
+		ps       domain.Posts
+		expected int
+	}{
+		{domain.Posts{a1, a2, a1}, 2},
+		{domain.Posts{a2}, 0},
+		{domain.Posts{a3}, 1},
+		{domain.Posts{a1}, 1},
+	}
+
+	for i, test := range tests {
+		actual := len(d.PostsOf(Driver{Name: "a"}, test.ps))
+		if actual != test.expected {
+			t.Errorf("unexpected number of deduped posts in %d: got %d, expect %d\n", i, actual, test.expected)
+		}
+	}
+}
+
+func TestDedupPostsOfDrivers(t *testing.T) {
+	d := newDedup(0)
+	social, fosstodon := Driver{Name: "mastodon:home", Args: []string{"mastodon.social"}}, Driver{Name: "mastodon:home", Args: []string{"fosstodon.org"}}
+	p := &domain.Post{ID: "1", Driver: "mastodon"}
+	if actual := len(d.PostsOf(social, domain.Posts{p})); actual != 1 {
+		t.Errorf("unexpected number of deduped posts of %s: got %d, expect 1\n", social, actual)
+	}
+	if actual := len(d.PostsOf(fosstodon, domain.Posts{p})); actual != 1 {
+		t.Errorf("unexpected number of deduped posts of %s: got %d, expect 1\n", fosstodon, actual)
+	}
+	if actual := len(d.PostsOf(social, domain.Posts{p})); actual != 0 {
+		t.Errorf("unexpected number of deduped posts of %s again: got %d, expect 0\n", social, actual)
+	}
+}
+
+func TestDedupPostsByURL(t *testing.T) {
+	var submission reddit.Post
+	if err := json.Unmarshal([]byte(`{
+		"name": "t3_dbm0gq", "subreddit_name_prefixed": "r/golang", "author": "tomocy", "title": "Go 1.13 is released",
+		"url": "https://blog.golang.org/go1.13?utm_source=reddit", "is_self": false,
+		"permalink": "/r/golang/comments/dbm0gq/go_113_is_released/", "created_utc": 1569888000.0
+	}`), &submission); err != nil {
+		t.Fatalf("failed to unmarshal reddit post: %s", err)
+	}
+	var self reddit.Post
+	if err := json.Unmarshal([]byte(`{
+		"name": "t3_dbm0gr", "subreddit_name_prefixed": "r/golang", "author": "gopher", "title": "Which editor do you use?",
+		"selftext": "I use vim", "url": "https://www.reddit.com/r/golang/comments/dbm0gr/which_editor_do_you_use/", "is_self": true,
+		"permalink": "/r/golang/comments/dbm0gr/which_editor_do_you_use/", "created_utc": 1569888000.0
+	}`), &self); err != nil {
+		t.Fatalf("failed to unmarshal reddit post: %s", err)
+	}
+	var story hackernews.Item
+	if err := json.Unmarshal([]byte(`{
+		"id": 21118500, "type": "story", "by": "tomocy", "title": "Go 1.13 is released",
+		"url": "https://blog.golang.org/go1.13", "time": 1569888000
+	}`), &story); err != nil {
+		t.Fatalf("failed to unmarshal hackernews item: %s", err)
+	}
+	var tweet twitter.Tweet
+	if err := json.Unmarshal([]byte(`{
+		"id_str": "1178906925347893248", "user": {"id_str": "1", "name": "tomocy", "screen_name": "tomocy"},
+		"full_text": "Go 1.13 is released https://t.co/abcdefg",
+		"entities": {"urls": [{"url": "https://t.co/abcdefg", "expanded_url": "https://www.blog.golang.org/go1.13/"}]},
+		"created_at": "Tue Oct 01 00:00:00 +0000 2019"
+	}`), &tweet); err != nil {
+		t.Fatalf("failed to unmarshal tweet: %s", err)
+	}
+	ps := domain.Posts{submission.Adapt(), self.Adapt(), story.Adapt(), tweet.Adapt()}
+
+	actuals := newDedup(0).PostsByURL(ps)
+	if len(actuals) != 2 {
+		t.Fatalf("unexpected len of posts by (*dedup).PostsByURL: got %d, expect 2\n", len(actuals))
+	}
+	if actuals[0].ID != ps[0].ID {
+		t.Errorf("unexpected id of posts[0] by (*dedup).PostsByURL: got %s, expect %s\n", actuals[0].ID, ps[0].ID)
+	}
+	expectedDrivers := []string{"reddit", "hackernews", "twitter"}
+	if len(actuals[0].Sources) != len(expectedDrivers) {
+		t.Fatalf("unexpected len of sources by (*dedup).PostsByURL: got %d, expect %d\n", len(actuals[0].Sources), len(expectedDrivers))
+	}
+	for i, d := range expectedDrivers {
+		if actuals[0].Sources[i].Driver != d {
+			t.Errorf("unexpected driver of sources[%d] by (*dedup).PostsByURL: got %s, expect %s\n", i, actuals[0].Sources[i].Driver, d)
+		}
+	}
+	if actuals[1].ID != ps[1].ID {
+		t.Errorf("unexpected id of posts[1] by (*dedup).PostsByURL: got %s, expect %s\n", actuals[1].ID, ps[1].ID)
+	}
+	for i, p := range ps {
+		if len(p.Sources) != 0 {
+			t.Errorf("unexpected len of sources of given posts[%d] after (*dedup).PostsByURL: got %d, expect 0\n", i, len(p.Sources))
+		}
+	}
+}
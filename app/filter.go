@@ -0,0 +1,176 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+)
+
+type Filter struct {
+	Includes, Excludes []*Rule
+}
+
+func (f *Filter) IsEmpty() bool {
+	return f == nil || len(f.Includes) <= 0 && len(f.Excludes) <= 0
+}
+
+func (f *Filter) Posts(ps domain.Posts) domain.Posts {
+	if f.IsEmpty() {
+		return ps
+	}
+
+	filtereds := make(domain.Posts, 0, len(ps))
+	for _, p := range ps {
+		if f.Match(p) {
+			filtereds = append(filtereds, p)
+		}
+	}
+
+	return filtereds
+}
+
+func (f *Filter) Match(p *domain.Post) bool {
+	if len(f.Includes) > 0 && !matchAny(f.Includes, p) {
+		return false
+	}
+
+	return !matchAny(f.Excludes, p)
+}
+
+func matchAny(rs []*Rule, p *domain.Post) bool {
+	for _, r := range rs {
+		if r.Match(p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	ruleEqual    = "="
+	ruleContain  = "~"
+	ruleRegexp   = "=~"
+	ruleLessThan = "<"
+	ruleMoreThan = ">"
+)
+
+func ParseRule(s string) (*Rule, error) {
+	i := strings.IndexAny(s, "=~<>")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid format of rule: %s: the format should be {field}{=|~|=~|<|>}{value}", s)
+	}
+
+	r := &Rule{
+		Field: strings.TrimSpace(s[:i]),
+		Op:    s[i : i+1],
+	}
+	if strings.HasPrefix(s[i:], ruleRegexp) {
+		r.Op = ruleRegexp
+	}
+	r.Value = s[i+len(r.Op):]
+
+	if err := r.compile(); err != nil {
+		return nil, fmt.Errorf("invalid rule: %s: %s", s, err)
+	}
+
+	return r, nil
+}
+
+type Rule struct {
+	Field, Op, Value string
+	re               *regexp.Regexp
+	age              time.Duration
+}
+
+func (r *Rule) compile() error {
+	switch r.Field {
+	case "driver", "user", "text", "tag", "channel", "url":
+	case "age":
+		if r.Op != ruleLessThan && r.Op != ruleMoreThan {
+			return fmt.Errorf("age can be compared only with < or >")
+		}
+		age, err := time.ParseDuration(r.Value)
+		if err != nil {
+			return err
+		}
+		r.age = age
+		return nil
+	default:
+		return fmt.Errorf("unknown field: %s", r.Field)
+	}
+
+	switch r.Op {
+	case ruleEqual, ruleContain:
+		return nil
+	case ruleRegexp:
+		re, err := regexp.Compile(r.Value)
+		if err != nil {
+			return err
+		}
+		r.re = re
+		return nil
+	default:
+		return fmt.Errorf("%s can not be compared with %s", r.Field, r.Op)
+	}
+}
+
+func (r *Rule) Match(p *domain.Post) bool {
+	if r.Field == "age" {
+		age := time.Since(p.CreatedAt)
+		if r.Op == ruleLessThan {
+			return age < r.age
+		}
+		return age > r.age
+	}
+
+	for _, v := range r.valuesOf(p) {
+		if r.matchValue(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Rule) valuesOf(p *domain.Post) []string {
+	switch r.Field {
+	case "driver":
+		return append(strings.Fields(p.Driver), p.Driver)
+	case "user":
+		if p.User == nil {
+			return nil
+		}
+		return []string{p.User.Name, p.User.Username}
+	case "text":
+		return []string{p.Title, p.Text}
+	case "tag":
+		return p.Tags
+	case "channel":
+		return []string{p.Channel}
+	case "url":
+		return []string{p.URL}
+	default:
+		return nil
+	}
+}
+
+func (r *Rule) matchValue(v string) bool {
+	switch r.Op {
+	case ruleEqual:
+		return strings.EqualFold(v, r.Value)
+	case ruleContain:
+		return strings.Contains(strings.ToLower(v), strings.ToLower(r.Value))
+	case ruleRegexp:
+		return r.re.MatchString(v)
+	default:
+		return false
+	}
+}
+
+func (r *Rule) String() string {
+	return r.Field + r.Op + r.Value
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+)
+
+func TestFilterMatch(t *testing.T) {
+	p := &domain.Post{
+		ID: "1", Driver: "github event", User: &domain.User{Name: "tomocy"},
+		Title: "golang/go", Text: "Go 2 proposal", Tags: []string{"proposal"},
+		CreatedAt: time.Now().Add(-1 * time.Hour),
+	}
+	tests := map[string]struct {
+		includes, excludes []string
+		expected           bool
+	}{
+		"no rules":            {expected: true},
+		"include by driver":   {includes: []string{"driver=github"}, expected: true},
+		"include by text":     {includes: []string{"text~GOLANG"}, expected: true},
+		"include by regexp":   {includes: []string{"text=~^Go [0-9]"}, expected: true},
+		"include by tag":      {includes: []string{"tag=bug", "tag=proposal"}, expected: true},
+		"not include":         {includes: []string{"user=gopher"}, expected: false},
+		"exclude by user":     {excludes: []string{"user=tomocy"}, expected: false},
+		"exclude by age":      {excludes: []string{"age<2h"}, expected: false},
+		"include and exclude": {includes: []string{"text~go"}, excludes: []string{"age>2h"}, expected: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := new(Filter)
+			for _, s := range test.includes {
+				f.Includes = append(f.Includes, mustParseRule(t, s))
+			}
+			for _, s := range test.excludes {
+				f.Excludes = append(f.Excludes, mustParseRule(t, s))
+			}
+			if actual := f.Match(p); actual != test.expected {
+				t.Errorf("unexpected result of (*Filter).Match: got %t, expect %t\n", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseRuleWithInvalidRule(t *testing.T) {
+	for _, s := range []string{"golang", "=golang", "name=tomocy", "age=24h", "age<1d", "text=~("} {
+		if _, err := ParseRule(s); err == nil {
+			t.Errorf("unexpected error by ParseRule with %s: got <nil>, expect error\n", s)
+		}
+	}
+}
+
+func mustParseRule(t *testing.T, s string) *Rule {
+	r, err := ParseRule(s)
+	if err != nil {
+		t.Fatalf("unexpected error by ParseRule: %s\n", err)
+	}
+
+	return r
+}
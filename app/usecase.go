@@ -3,20 +3,85 @@ package app
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/tomocy/smoothie/domain"
 )
 
-func NewPostUsecase(repos map[string]domain.PostRepo) *PostUsecase {
-	return &PostUsecase{
-		repos: repos,
+func NewPostUsecase(repos map[string]domain.PostRepo, opts ...Option) *PostUsecase {
+	u := &PostUsecase{
+		repos:       repos,
+		concurrency: 4,
+		dedupSize:   1024,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
+}
+
+type Option func(*PostUsecase)
+
+func WithConcurrency(n int) Option {
+	return func(u *PostUsecase) {
+		if n <= 0 {
+			return
+		}
+		u.concurrency = n
+	}
+}
+
+func WithHistory(repo domain.HistoryRepo) Option {
+	return func(u *PostUsecase) {
+		u.history = repo
+	}
+}
+
+func WithWarning(warn func(error)) Option {
+	return func(u *PostUsecase) {
+		u.warn = warn
+	}
+}
+
+func WithMarks(repo domain.MarkRepo) Option {
+	return func(u *PostUsecase) {
+		u.marks = repo
+	}
+}
+
+func WithDedupSize(n int) Option {
+	return func(u *PostUsecase) {
+		if n <= 0 {
+			return
+		}
+		u.dedupSize = n
+	}
+}
+
+func WithURLDedup() Option {
+	return func(u *PostUsecase) {
+		u.dedupByURL = true
+	}
+}
+
+func WithFilter(f *Filter) Option {
+	return func(u *PostUsecase) {
+		u.filter = f
 	}
 }
 
 type PostUsecase struct {
-	repos map[string]domain.PostRepo
+	repos       map[string]domain.PostRepo
+	history     domain.HistoryRepo
+	marks       domain.MarkRepo
+	concurrency int
+	dedupSize   int
+	dedupByURL  bool
+	filter      *Filter
+	warn        func(error)
 }
 
 func (u *PostUsecase) StreamPostsOfDrivers(ctx context.Context, ds ...Driver) (<-chan domain.Posts, <-chan error) {
@@ -25,7 +90,36 @@ func (u *PostUsecase) StreamPostsOfDrivers(ctx context.Context, ds ...Driver) (<
 		psChs[i], errChs[i] = u.streamPosts(ctx, d)
 	}
 
-	return u.fanInPosts(ctx, psChs...), u.fanInErrors(ctx, errChs...)
+	dedup := newDedup(u.dedupSize)
+	psCh := u.pipePosts(ctx, u.fanInPosts(ctx, psChs...), func(ps domain.Posts) domain.Posts {
+		if u.dedupByURL {
+			ps = dedup.PostsByURL(ps)
+		}
+		return u.filter.Posts(ps)
+	})
+
+	return psCh, u.fanInErrors(ctx, errChs...)
+}
+
+func (u *PostUsecase) pipePosts(ctx context.Context, psCh <-chan domain.Posts, pipe func(domain.Posts) domain.Posts) <-chan domain.Posts {
+	pipedCh := make(chan domain.Posts)
+	go func() {
+		defer close(pipedCh)
+		for ps := range psCh {
+			piped := pipe(ps)
+			if len(piped) <= 0 {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case pipedCh <- piped:
+			}
+		}
+	}()
+
+	return pipedCh
 }
 
 func (u *PostUsecase) streamPosts(ctx context.Context, d Driver) (<-chan domain.Posts, <-chan error) {
@@ -42,44 +136,77 @@ func (u *PostUsecase) streamPosts(ctx context.Context, d Driver) (<-chan domain.
 		return nil, errCh
 	}
 
-	return repo.StreamPosts(ctx, d.Args)
+	psCh, errCh := repo.StreamPosts(ctx, d.Args, d.Interval)
+	dedup := newDedup(u.dedupSize)
+	return u.pipePosts(ctx, psCh, func(ps domain.Posts) domain.Posts {
+		deduped := dedup.PostsOf(d, keyPosts(d, ps))
+		u.recordPosts(d, deduped)
+		return deduped
+	}), errCh
+}
+
+func keyPosts(d Driver, ps domain.Posts) domain.Posts {
+	keyeds := make(domain.Posts, len(ps))
+	for i, p := range ps {
+		keyed := *p
+		keyed.DriverKey = d.String()
+		keyeds[i] = &keyed
+	}
+
+	return keyeds
+}
+
+func (u *PostUsecase) recordPosts(d Driver, ps domain.Posts) {
+	if u.history == nil || len(ps) <= 0 {
+		return
+	}
+
+	if err := u.history.SavePosts(d.String(), u.filter.Posts(ps)); err != nil {
+		u.warnf("failed to save posts of %s: %s", d, err)
+	}
+}
+
+func (u *PostUsecase) warnf(format string, args ...interface{}) {
+	if u.warn == nil {
+		return
+	}
+
+	u.warn(fmt.Errorf(format, args...))
 }
 
 func (u *PostUsecase) fanInPosts(ctx context.Context, chs ...<-chan domain.Posts) <-chan domain.Posts {
 	fannedInCh := make(chan domain.Posts)
 	go func() {
 		defer close(fannedInCh)
-		fanIn := func(dst chan<- domain.Posts, srces []<-chan domain.Posts, doSkip bool) {
-			var fannedIn domain.Posts
-			for _, src := range srces {
-				if doSkip {
+		var wg sync.WaitGroup
+		for _, ch := range chs {
+			wg.Add(1)
+			go func(ch <-chan domain.Posts) {
+				defer wg.Done()
+				for {
 					select {
-					case ps := <-src:
-						fannedIn = append(fannedIn, ps...)
-					default:
+					case <-ctx.Done():
+						return
+					case ps, ok := <-ch:
+						if !ok {
+							return
+						}
+						if len(ps) <= 0 {
+							continue
+						}
+
+						ps.SortByNewest()
+						select {
+						case <-ctx.Done():
+							return
+						case fannedInCh <- ps:
+						}
 					}
-				} else {
-					ps := <-src
-					fannedIn = append(fannedIn, ps...)
 				}
-			}
-			if len(fannedIn) <= 0 {
-				return
-			}
-
-			fannedIn.SortByNewest()
-			dst <- fannedIn
+			}(ch)
 		}
 
-		fanIn(fannedInCh, chs, false)
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(1 * time.Minute):
-				fanIn(fannedInCh, chs, true)
-			}
-		}
+		wg.Wait()
 	}()
 
 	return fannedInCh
@@ -106,32 +233,327 @@ func (u *PostUsecase) fanInErrors(ctx context.Context, chs ...<-chan error) <-ch
 	return fannedInCh
 }
 
-func (u *PostUsecase) FetchPostsOfDrivers(ds ...Driver) (domain.Posts, error) {
-	var fetcheds domain.Posts
+func (u *PostUsecase) AuthorizeDrivers(ctx context.Context, ds ...Driver) ([]Driver, error) {
+	var authorizeds []Driver
+	var driverErrs DriverErrors
 	for _, d := range ds {
-		ps, err := u.fetchPost(d)
+		if err := u.authorize(ctx, d); err != nil {
+			driverErrs = append(driverErrs, &DriverError{
+				Driver: d, Err: err,
+			})
+			continue
+		}
+
+		authorizeds = append(authorizeds, d)
+	}
+
+	if len(driverErrs) > 0 {
+		return authorizeds, driverErrs
+	}
+
+	return authorizeds, nil
+}
+
+func (u *PostUsecase) authorize(ctx context.Context, d Driver) error {
+	repo, ok := u.repos[d.Name]
+	if !ok {
+		return fmt.Errorf("unknown driver: %s", d)
+	}
+	authorizer, ok := repo.(domain.Authorizer)
+	if !ok {
+		return nil
+	}
+
+	return authorizer.Authorize(ctx, d.Args)
+}
+
+func (u *PostUsecase) FetchPostsOfDrivers(ctx context.Context, ds ...Driver) (domain.Posts, error) {
+	return u.fetchPostsOfDrivers(ctx, ds, false)
+}
+
+func (u *PostUsecase) FetchUnreadPostsOfDrivers(ctx context.Context, ds ...Driver) (domain.Posts, error) {
+	if u.marks == nil {
+		return nil, fmt.Errorf("marks are not enabled")
+	}
+
+	return u.fetchPostsOfDrivers(ctx, ds, true)
+}
+
+func (u *PostUsecase) fetchPostsOfDrivers(ctx context.Context, ds []Driver, onlyUnread bool) (domain.Posts, error) {
+	pss, errs := u.fetchPostsConcurrently(ctx, ds, u.fetchPosts)
+	if u.marks != nil {
+		for i, d := range ds {
+			if errs[i] != nil {
+				continue
+			}
+			pss[i] = u.markPostsAsFetched(d, pss[i], onlyUnread)
+		}
+	}
+
+	return u.mergePosts(ds, pss, errs)
+}
+
+func (u *PostUsecase) SearchPostsOfDrivers(ctx context.Context, q string, ds ...Driver) (domain.Posts, error) {
+	pss, errs := u.fetchPostsConcurrently(ctx, ds, func(ctx context.Context, d Driver) (domain.Posts, error) {
+		return u.searchPosts(ctx, d, q)
+	})
+
+	return u.mergePosts(ds, pss, errs)
+}
+
+func (u *PostUsecase) mergePosts(ds []Driver, pss []domain.Posts, errs []error) (domain.Posts, error) {
+	var mergeds domain.Posts
+	var driverErrs DriverErrors
+	dedup := newDedup(0)
+	for i, d := range ds {
+		if errs[i] != nil {
+			driverErrs = append(driverErrs, &DriverError{
+				Driver: d, Err: errs[i],
+			})
+			continue
+		}
+
+		deduped := dedup.PostsOf(d, keyPosts(d, pss[i]))
+		u.recordPosts(d, deduped)
+		mergeds = append(mergeds, deduped...)
+	}
+
+	mergeds.SortByNewest()
+	if u.dedupByURL {
+		mergeds = dedup.PostsByURL(mergeds)
+	}
+	mergeds = u.filter.Posts(mergeds)
+
+	if len(driverErrs) > 0 {
+		return mergeds, driverErrs
+	}
+
+	return mergeds, nil
+}
+
+func (u *PostUsecase) markPostsAsFetched(d Driver, ps domain.Posts, onlyUnread bool) domain.Posts {
+	ms, err := u.marks.FindMarks(d.String())
+	if err != nil {
+		u.warnf("failed to find marks of %s: %s", d, err)
+		return ps
+	}
+	ms.Fetched = laterMark(ms.Fetched, domain.MarkOf(ps))
+	if err := u.marks.SaveMarks(d.String(), ms); err != nil {
+		u.warnf("failed to save marks of %s: %s", d, err)
+	}
+
+	if !onlyUnread {
+		return ps
+	}
+
+	return ms.Read.Unseens(ps)
+}
+
+func (u *PostUsecase) MarkDriversAsRead(ds ...Driver) error {
+	if u.marks == nil {
+		return fmt.Errorf("marks are not enabled")
+	}
+
+	for _, d := range ds {
+		ms, err := u.marks.FindMarks(d.String())
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch post of drivers: %s", err)
+			return fmt.Errorf("failed to find marks of %s: %s", d, err)
+		}
+		ms.Read = laterMark(ms.Read, ms.Fetched)
+		if err := u.marks.SaveMarks(d.String(), ms); err != nil {
+			return fmt.Errorf("failed to save marks of %s: %s", d, err)
 		}
+	}
+
+	return nil
+}
 
-		fetcheds = append(fetcheds, ps...)
+func laterMark(a, b domain.Mark) domain.Mark {
+	if b.CreatedAt.After(a.CreatedAt) {
+		return b
 	}
 
-	fetcheds.SortByNewest()
+	return a
+}
 
-	return fetcheds, nil
+func (u *PostUsecase) fetchPostsConcurrently(ctx context.Context, ds []Driver, fetch func(context.Context, Driver) (domain.Posts, error)) ([]domain.Posts, []error) {
+	pss, errs := make([]domain.Posts, len(ds)), make([]error, len(ds))
+	sem := make(chan struct{}, u.concurrency)
+	var wg sync.WaitGroup
+	for i, d := range ds {
+		wg.Add(1)
+		go func(i int, d Driver) {
+			defer wg.Done()
+			select {
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			case sem <- struct{}{}:
+			}
+			defer func() {
+				<-sem
+			}()
+
+			pss[i], errs[i] = fetch(ctx, d)
+		}(i, d)
+	}
+
+	wg.Wait()
+
+	return pss, errs
 }
 
-func (u *PostUsecase) fetchPost(d Driver) (domain.Posts, error) {
+func (u *PostUsecase) fetchPosts(ctx context.Context, d Driver) (domain.Posts, error) {
 	repo, ok := u.repos[d.Name]
 	if !ok {
 		return nil, fmt.Errorf("unknown driver: %s", d)
 	}
 
-	return repo.FetchPosts(d.Args)
+	return awaitPosts(ctx, func() (domain.Posts, error) {
+		return repo.FetchPosts(ctx, d.Args)
+	})
+}
+
+func (u *PostUsecase) searchPosts(ctx context.Context, d Driver, q string) (domain.Posts, error) {
+	repo, ok := u.repos[d.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown driver: %s", d)
+	}
+	searcher, ok := repo.(domain.Searcher)
+	if !ok {
+		return nil, fmt.Errorf("search is not supported")
+	}
+
+	return awaitPosts(ctx, func() (domain.Posts, error) {
+		return searcher.SearchPosts(ctx, d.Args, q)
+	})
+}
+
+func awaitPosts(ctx context.Context, fetch func() (domain.Posts, error)) (domain.Posts, error) {
+	psCh, errCh := make(chan domain.Posts, 1), make(chan error, 1)
+	go func() {
+		ps, err := fetch()
+		if err != nil {
+			errCh <- err
+			return
+		}
+
+		psCh <- ps
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case ps := <-psCh:
+		return ps, nil
+	case err := <-errCh:
+		return nil, err
+	}
+}
+
+func (u *PostUsecase) CreatePostOfDrivers(ctx context.Context, text string, ds ...Driver) (domain.Posts, error) {
+	pss, errs := u.fetchPostsConcurrently(ctx, ds, func(ctx context.Context, d Driver) (domain.Posts, error) {
+		return u.writePost(d, func(w domain.PostWriter) (*domain.Post, error) {
+			return w.CreatePost(ctx, d.Args, text)
+		})
+	})
+
+	var createds domain.Posts
+	var driverErrs DriverErrors
+	for i, d := range ds {
+		if errs[i] != nil {
+			driverErrs = append(driverErrs, &DriverError{
+				Driver: d, Err: errs[i],
+			})
+			continue
+		}
+
+		createds = append(createds, pss[i]...)
+	}
+
+	if len(driverErrs) > 0 {
+		return createds, driverErrs
+	}
+
+	return createds, nil
+}
+
+func (u *PostUsecase) ReplyToPost(ctx context.Context, d Driver, id, text string) (*domain.Post, error) {
+	ps, err := u.writePost(d, func(w domain.PostWriter) (*domain.Post, error) {
+		return w.Reply(ctx, d.Args, id, text)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ps[0], nil
+}
+
+func (u *PostUsecase) ReactToPost(ctx context.Context, d Driver, id string, r domain.Reaction) error {
+	repo, ok := u.repos[d.Name]
+	if !ok {
+		return fmt.Errorf("unknown driver: %s", d)
+	}
+	reactor, ok := repo.(domain.Reactor)
+	if !ok {
+		return fmt.Errorf("reaction is not supported")
+	}
+
+	return reactor.React(ctx, d.Args, id, r)
+}
+
+func (u *PostUsecase) writePost(d Driver, write func(domain.PostWriter) (*domain.Post, error)) (domain.Posts, error) {
+	repo, ok := u.repos[d.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown driver: %s", d)
+	}
+	w, ok := repo.(domain.PostWriter)
+	if !ok {
+		return nil, fmt.Errorf("posting is not supported")
+	}
+
+	p, err := write(w)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.Posts{p}, nil
+}
+
+func (u *PostUsecase) FetchHistory(q domain.HistoryQuery) (domain.Posts, error) {
+	if u.history == nil {
+		return nil, fmt.Errorf("history is not enabled")
+	}
+
+	return u.history.FindPosts(q)
 }
 
 type Driver struct {
-	Name string
-	Args []string
+	Name     string
+	Args     []string
+	Interval time.Duration
+}
+
+func (d Driver) String() string {
+	return strings.Join(append([]string{d.Name}, d.Args...), ":")
+}
+
+type DriverErrors []*DriverError
+
+func (es DriverErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+
+	return fmt.Sprintf("failed to fetch posts of drivers: %s", strings.Join(msgs, ", "))
+}
+
+type DriverError struct {
+	Driver Driver
+	Err    error
+}
+
+func (e *DriverError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Driver, e.Err)
 }
@@ -2,7 +2,9 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,15 +16,18 @@ func TestStremPostsOfDrivers(t *testing.T) {
 	expecteds := domain.Posts{
 		{ID: "1", Driver: "a", Text: "one", CreatedAt: expectedDate.Add(2 * time.Hour)},
 		{ID: "1", Driver: "b", Text: "one", CreatedAt: expectedDate.Add(2 * time.Hour)},
+		{ID: "2", Driver: "a", Text: "two", CreatedAt: expectedDate.Add(1 * time.Hour)},
+		{ID: "2", Driver: "b", Text: "two", CreatedAt: expectedDate.Add(1 * time.Hour)},
 	}
 	u := newMockPostUsecase()
 	ctx, cancelFn := context.WithCancel(context.Background())
 	psCh, errCh := u.StreamPostsOfDrivers(ctx, Driver{Name: "a"}, Driver{Name: "b"})
 	var actuals domain.Posts
+	timeout := time.After(600 * time.Millisecond)
 waiting:
 	for {
 		select {
-		case <-time.After(600 * time.Millisecond):
+		case <-timeout:
 			cancelFn()
 		case ps, ok := <-psCh:
 			if !ok {
@@ -36,11 +41,28 @@ waiting:
 			}
 		}
 	}
+	actuals.SortByNewest()
 	if err := assertPosts(actuals, expecteds); err != nil {
 		t.Errorf("unexpected posts by (*PostUsecase).StreamPosts: %s\n", err)
 	}
 }
 
+func TestAuthorizeDrivers(t *testing.T) {
+	u := NewPostUsecase(map[string]domain.PostRepo{
+		"a": &mockAuthorizer{mock: newMock("a")},
+		"b": &mockAuthorizer{mock: newMock("b"), err: errors.New("denied")},
+		"c": newMock("c"),
+	})
+	actuals, err := u.AuthorizeDrivers(context.Background(), Driver{Name: "a"}, Driver{Name: "b"}, Driver{Name: "c"})
+	errs, ok := err.(DriverErrors)
+	if !ok || len(errs) != 1 || errs[0].Driver.Name != "b" {
+		t.Errorf("unexpected error by (*PostUsecase).AuthorizeDrivers: got %v, expect error of driver b\n", err)
+	}
+	if len(actuals) != 2 || actuals[0].Name != "a" || actuals[1].Name != "c" {
+		t.Errorf("unexpected drivers by (*PostUsecase).AuthorizeDrivers: got %v, expect a and c\n", actuals)
+	}
+}
+
 func TestFetchPostsOfDrivers(t *testing.T) {
 	expectedDate := time.Date(2019, 8, 13, 0, 0, 0, 0, time.Local)
 	expecteds := domain.Posts{
@@ -52,7 +74,7 @@ func TestFetchPostsOfDrivers(t *testing.T) {
 		{ID: "3", Driver: "b", Text: "three", CreatedAt: expectedDate},
 	}
 	u := newMockPostUsecase()
-	actuals, err := u.FetchPostsOfDrivers(Driver{Name: "a"}, Driver{Name: "b"})
+	actuals, err := u.FetchPostsOfDrivers(context.Background(), Driver{Name: "a"}, Driver{Name: "b"})
 	if err != nil {
 		t.Errorf("unexpected error by (*PostUsecase).FetchPostsOfDrivers: got %s, expect <nil>\n", err)
 	}
@@ -61,6 +83,265 @@ func TestFetchPostsOfDrivers(t *testing.T) {
 	}
 }
 
+func TestFetchPostsOfDriversWithArgs(t *testing.T) {
+	u := newMockPostUsecase()
+	social, fosstodon := Driver{Name: "a", Args: []string{"mastodon.social"}}, Driver{Name: "a", Args: []string{"fosstodon.org"}}
+	actuals, err := u.FetchPostsOfDrivers(context.Background(), social, fosstodon)
+	if err != nil {
+		t.Fatalf("unexpected error by (*PostUsecase).FetchPostsOfDrivers: got %s, expect <nil>\n", err)
+	}
+	if len(actuals) != 6 {
+		t.Fatalf("unexpected len of posts by (*PostUsecase).FetchPostsOfDrivers: got %d, expect 6\n", len(actuals))
+	}
+	keys := make(map[string]int)
+	for _, p := range actuals {
+		keys[p.DriverKey+":"+p.ID]++
+	}
+	for _, d := range []Driver{social, fosstodon} {
+		for _, id := range []string{"1", "2", "3"} {
+			if keys[d.String()+":"+id] != 1 {
+				t.Errorf("unexpected number of posts of %s:%s by (*PostUsecase).FetchPostsOfDrivers: got %d, expect 1\n", d, id, keys[d.String()+":"+id])
+			}
+		}
+	}
+}
+
+func TestFetchPostsOfDriversWithFailedDriver(t *testing.T) {
+	expectedDate := time.Date(2019, 8, 13, 0, 0, 0, 0, time.Local)
+	expecteds := domain.Posts{
+		{ID: "1", Driver: "a", Text: "one", CreatedAt: expectedDate.Add(2 * time.Hour)},
+		{ID: "2", Driver: "a", Text: "two", CreatedAt: expectedDate.Add(1 * time.Hour)},
+		{ID: "3", Driver: "a", Text: "three", CreatedAt: expectedDate},
+	}
+	u := newMockPostUsecase()
+	u.repos["b"].(*mock).err = errors.New("expired token")
+	actuals, err := u.FetchPostsOfDrivers(context.Background(), Driver{Name: "a"}, Driver{Name: "b"}, Driver{Name: "d", Args: []string{"x"}})
+	errs, ok := err.(DriverErrors)
+	if !ok {
+		t.Fatalf("unexpected error by (*PostUsecase).FetchPostsOfDrivers: got %v, expect DriverErrors\n", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("unexpected len of errors by (*PostUsecase).FetchPostsOfDrivers: got %d, expect 2\n", len(errs))
+	}
+	if errs[0].Driver.String() != "b" {
+		t.Errorf("unexpected driver of errors[0]: got %s, expect b\n", errs[0].Driver)
+	}
+	if errs[1].Driver.String() != "d:x" {
+		t.Errorf("unexpected driver of errors[1]: got %s, expect d:x\n", errs[1].Driver)
+	}
+	if err := assertPosts(actuals, expecteds); err != nil {
+		t.Errorf("unexpected posts by (*PostUsecase).FetchPostsOfDrivers: %s\n", err)
+	}
+}
+
+func TestFetchPostsOfDriversWithDeadline(t *testing.T) {
+	expectedDate := time.Date(2019, 8, 13, 0, 0, 0, 0, time.Local)
+	expecteds := domain.Posts{
+		{ID: "1", Driver: "a", Text: "one", CreatedAt: expectedDate.Add(2 * time.Hour)},
+		{ID: "2", Driver: "a", Text: "two", CreatedAt: expectedDate.Add(1 * time.Hour)},
+		{ID: "3", Driver: "a", Text: "three", CreatedAt: expectedDate},
+	}
+	u := newMockPostUsecase()
+	u.repos["b"].(*mock).delay = time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	actuals, err := u.FetchPostsOfDrivers(ctx, Driver{Name: "a"}, Driver{Name: "b"})
+	if elapsed := time.Since(started); 500*time.Millisecond <= elapsed {
+		t.Errorf("unexpected elapsed time of (*PostUsecase).FetchPostsOfDrivers: got %s, expect less than 500ms\n", elapsed)
+	}
+	errs, ok := err.(DriverErrors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("unexpected error by (*PostUsecase).FetchPostsOfDrivers: got %v, expect an error of driver b\n", err)
+	}
+	if errs[0].Err != context.DeadlineExceeded {
+		t.Errorf("unexpected error of driver b: got %s, expect %s\n", errs[0].Err, context.DeadlineExceeded)
+	}
+	if err := assertPosts(actuals, expecteds); err != nil {
+		t.Errorf("unexpected posts by (*PostUsecase).FetchPostsOfDrivers: %s\n", err)
+	}
+}
+
+func TestFetchPostsOfDriversWithHistory(t *testing.T) {
+	expectedDate := time.Date(2019, 8, 13, 0, 0, 0, 0, time.Local)
+	expecteds := domain.Posts{
+		{ID: "1", Driver: "a", Text: "one", CreatedAt: expectedDate.Add(2 * time.Hour)},
+		{ID: "2", Driver: "a", Text: "two", CreatedAt: expectedDate.Add(1 * time.Hour)},
+		{ID: "3", Driver: "a", Text: "three", CreatedAt: expectedDate},
+	}
+	h := new(mockHistory)
+	u := NewPostUsecase(map[string]domain.PostRepo{
+		"a": newMock("a"),
+	}, WithHistory(h))
+	if _, err := u.FetchPostsOfDrivers(context.Background(), Driver{Name: "a"}); err != nil {
+		t.Fatalf("unexpected error by (*PostUsecase).FetchPostsOfDrivers: got %s, expect <nil>\n", err)
+	}
+
+	actuals, err := u.FetchHistory(domain.HistoryQuery{})
+	if err != nil {
+		t.Errorf("unexpected error by (*PostUsecase).FetchHistory: got %s, expect <nil>\n", err)
+	}
+	if err := assertPosts(actuals, expecteds); err != nil {
+		t.Errorf("unexpected posts by (*PostUsecase).FetchHistory: %s\n", err)
+	}
+}
+
+func TestFetchPostsOfDriversWithFailedHistory(t *testing.T) {
+	var warnings []error
+	u := NewPostUsecase(map[string]domain.PostRepo{
+		"a": newMock("a"),
+	}, WithHistory(&mockHistory{err: errors.New("disk full")}), WithWarning(func(err error) {
+		warnings = append(warnings, err)
+	}))
+	actuals, err := u.FetchPostsOfDrivers(context.Background(), Driver{Name: "a"}, Driver{Name: "b"})
+	errs, ok := err.(DriverErrors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("unexpected error by (*PostUsecase).FetchPostsOfDrivers: got %v, expect an error of driver b\n", err)
+	}
+	if len(actuals) != 3 {
+		t.Errorf("unexpected len of posts by (*PostUsecase).FetchPostsOfDrivers: got %d, expect 3\n", len(actuals))
+	}
+	if len(warnings) != 1 {
+		t.Errorf("unexpected len of warnings by (*PostUsecase).FetchPostsOfDrivers: got %d, expect 1\n", len(warnings))
+	}
+}
+
+func TestFetchUnreadPostsOfDrivers(t *testing.T) {
+	expectedDate := time.Date(2019, 8, 13, 0, 0, 0, 0, time.Local)
+	repo := newMock("a")
+	u := NewPostUsecase(map[string]domain.PostRepo{
+		"a": repo,
+	}, WithMarks(make(mockMarks)))
+	if _, err := u.FetchUnreadPostsOfDrivers(context.Background(), Driver{Name: "a"}); err != nil {
+		t.Fatalf("unexpected error by (*PostUsecase).FetchUnreadPostsOfDrivers: got %s, expect <nil>\n", err)
+	}
+	if err := u.MarkDriversAsRead(Driver{Name: "a"}); err != nil {
+		t.Fatalf("unexpected error by (*PostUsecase).MarkDriversAsRead: got %s, expect <nil>\n", err)
+	}
+
+	repo.ps = append(domain.Posts{
+		{ID: "4", Driver: "a", Text: "four", CreatedAt: expectedDate.Add(3 * time.Hour)},
+	}, repo.ps...)
+	expecteds := repo.ps[:1]
+	actuals, err := u.FetchUnreadPostsOfDrivers(context.Background(), Driver{Name: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error by (*PostUsecase).FetchUnreadPostsOfDrivers: got %s, expect <nil>\n", err)
+	}
+	if err := assertPosts(actuals, expecteds); err != nil {
+		t.Errorf("unexpected posts by (*PostUsecase).FetchUnreadPostsOfDrivers: %s\n", err)
+	}
+}
+
+func TestFetchPostsOfDriversWithFailedMarks(t *testing.T) {
+	var warnings []error
+	u := NewPostUsecase(map[string]domain.PostRepo{
+		"a": newMock("a"),
+	}, WithMarks(failingMarks{err: errors.New("corrupt marks")}), WithWarning(func(err error) {
+		warnings = append(warnings, err)
+	}))
+	actuals, err := u.FetchUnreadPostsOfDrivers(context.Background(), Driver{Name: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error by (*PostUsecase).FetchUnreadPostsOfDrivers: got %s, expect <nil>\n", err)
+	}
+	if len(actuals) != 3 {
+		t.Errorf("unexpected len of posts by (*PostUsecase).FetchUnreadPostsOfDrivers: got %d, expect 3\n", len(actuals))
+	}
+	if len(warnings) != 1 {
+		t.Errorf("unexpected len of warnings by (*PostUsecase).FetchUnreadPostsOfDrivers: got %d, expect 1\n", len(warnings))
+	}
+}
+
+func TestFetchPostsOfDriversWithURLDedup(t *testing.T) {
+	expectedDate := time.Date(2019, 8, 13, 0, 0, 0, 0, time.Local)
+	u := NewPostUsecase(map[string]domain.PostRepo{
+		"a": &mock{
+			ps: domain.Posts{
+				{ID: "1", Driver: "a", URL: "https://www.example.com/go?utm_source=a", CreatedAt: expectedDate.Add(1 * time.Hour)},
+			},
+		},
+		"b": &mock{
+			ps: domain.Posts{
+				{ID: "1", Driver: "b", URL: "https://example.com/go/", CreatedAt: expectedDate},
+				{ID: "2", Driver: "b", URL: "https://example.com/rust", CreatedAt: expectedDate},
+			},
+		},
+	}, WithURLDedup())
+	actuals, err := u.FetchPostsOfDrivers(context.Background(), Driver{Name: "a"}, Driver{Name: "b"})
+	if err != nil {
+		t.Fatalf("unexpected error by (*PostUsecase).FetchPostsOfDrivers: got %s, expect <nil>\n", err)
+	}
+	if len(actuals) != 2 {
+		t.Fatalf("unexpected number of posts by (*PostUsecase).FetchPostsOfDrivers: got %d, expect 2\n", len(actuals))
+	}
+	if actuals[0].Driver != "a" || len(actuals[0].Sources) != 2 || actuals[0].Sources[1].Driver != "b" {
+		t.Errorf("unexpected collapsed post by (*PostUsecase).FetchPostsOfDrivers: got %v\n", actuals[0])
+	}
+}
+
+func TestSearchPostsOfDrivers(t *testing.T) {
+	expectedDate := time.Date(2019, 8, 13, 0, 0, 0, 0, time.Local)
+	expecteds := domain.Posts{
+		{ID: "1", Driver: "a", Text: "one golang", CreatedAt: expectedDate},
+	}
+	u := NewPostUsecase(map[string]domain.PostRepo{
+		"a": &mockSearcher{mock: newMock("a"), ps: expecteds},
+		"b": newMock("b"),
+	})
+	actuals, err := u.SearchPostsOfDrivers(context.Background(), "golang", Driver{Name: "a"}, Driver{Name: "b"})
+	errs, ok := err.(DriverErrors)
+	if !ok || len(errs) != 1 || errs[0].Driver.Name != "b" {
+		t.Errorf("unexpected error by (*PostUsecase).SearchPostsOfDrivers: got %v, expect error of driver b\n", err)
+	}
+	if err := assertPosts(actuals, expecteds); err != nil {
+		t.Errorf("unexpected posts by (*PostUsecase).SearchPostsOfDrivers: %s\n", err)
+	}
+}
+
+func TestCreatePostOfDrivers(t *testing.T) {
+	u := NewPostUsecase(map[string]domain.PostRepo{
+		"a": &mockWriter{mock: newMock("a")},
+		"b": newMock("b"),
+	})
+	actuals, err := u.CreatePostOfDrivers(context.Background(), "hello", Driver{Name: "a"}, Driver{Name: "b"})
+	errs, ok := err.(DriverErrors)
+	if !ok || len(errs) != 1 || errs[0].Driver.Name != "b" {
+		t.Errorf("unexpected error by (*PostUsecase).CreatePostOfDrivers: got %v, expect error of driver b\n", err)
+	}
+	if len(actuals) != 1 || actuals[0].Driver != "a" || actuals[0].Text != "hello" {
+		t.Errorf("unexpected posts by (*PostUsecase).CreatePostOfDrivers: got %v\n", actuals)
+	}
+}
+
+func TestReplyToPost(t *testing.T) {
+	u := NewPostUsecase(map[string]domain.PostRepo{
+		"a": &mockWriter{mock: newMock("a")},
+	})
+	actual, err := u.ReplyToPost(context.Background(), Driver{Name: "a"}, "1", "hello")
+	if err != nil {
+		t.Fatalf("unexpected error by (*PostUsecase).ReplyToPost: got %s, expect <nil>\n", err)
+	}
+	if actual.ParentID != "1" || actual.Text != "hello" {
+		t.Errorf("unexpected post by (*PostUsecase).ReplyToPost: got %v\n", actual)
+	}
+}
+
+func TestReactToPost(t *testing.T) {
+	u := NewPostUsecase(map[string]domain.PostRepo{
+		"a": &mockWriter{mock: newMock("a")},
+		"b": newMock("b"),
+	})
+	if err := u.ReactToPost(context.Background(), Driver{Name: "a"}, "1", domain.ReactionLike); err != nil {
+		t.Errorf("unexpected error by (*PostUsecase).ReactToPost: got %s, expect <nil>\n", err)
+	}
+	if err := u.ReactToPost(context.Background(), Driver{Name: "a"}, "1", domain.ReactionArchive); err == nil {
+		t.Errorf("unexpected error by (*PostUsecase).ReactToPost: got <nil>, expect error of unsupported reaction\n")
+	}
+	if err := u.ReactToPost(context.Background(), Driver{Name: "b"}, "1", domain.ReactionLike); err == nil {
+		t.Errorf("unexpected error by (*PostUsecase).ReactToPost: got <nil>, expect error of unsupported driver\n")
+	}
+}
+
 func newMockPostUsecase() *PostUsecase {
 	ds := [...]string{"a", "b", "c"}
 	repoA, repoB, repoC := newMock(ds[0]), newMock(ds[1]), newMock(ds[2])
@@ -82,10 +363,12 @@ func newMock(d string) *mock {
 }
 
 type mock struct {
-	ps domain.Posts
+	ps    domain.Posts
+	err   error
+	delay time.Duration
 }
 
-func (m *mock) StreamPosts(ctx context.Context, args []string) (<-chan domain.Posts, <-chan error) {
+func (m *mock) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
 	psCh, errCh := make(chan domain.Posts), make(chan error)
 	go func() {
 		defer func() {
@@ -110,10 +393,104 @@ func (m *mock) StreamPosts(ctx context.Context, args []string) (<-chan domain.Po
 	return psCh, errCh
 }
 
-func (m *mock) FetchPosts(args []string) (domain.Posts, error) {
+func (m *mock) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(m.delay):
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+
 	return m.ps, nil
 }
 
+type mockSearcher struct {
+	*mock
+	ps domain.Posts
+}
+
+func (m *mockSearcher) SearchPosts(ctx context.Context, args []string, q string) (domain.Posts, error) {
+	var searcheds domain.Posts
+	for _, p := range m.ps {
+		if strings.Contains(p.Text, q) {
+			searcheds = append(searcheds, p)
+		}
+	}
+
+	return searcheds, nil
+}
+
+type mockWriter struct {
+	*mock
+}
+
+func (m *mockWriter) CreatePost(ctx context.Context, args []string, text string) (*domain.Post, error) {
+	return &domain.Post{ID: "1", Driver: "a", Text: text, CreatedAt: time.Now()}, nil
+}
+
+func (m *mockWriter) Reply(ctx context.Context, args []string, id, text string) (*domain.Post, error) {
+	return &domain.Post{ID: "2", Driver: "a", Text: text, ParentID: id, CreatedAt: time.Now()}, nil
+}
+
+func (m *mockWriter) React(ctx context.Context, args []string, id string, r domain.Reaction) error {
+	if r != domain.ReactionLike {
+		return &domain.UnsupportedReactionError{Reaction: r}
+	}
+
+	return nil
+}
+
+type mockAuthorizer struct {
+	*mock
+	err error
+}
+
+func (m *mockAuthorizer) Authorize(ctx context.Context, args []string) error {
+	return m.err
+}
+
+type mockHistory struct {
+	ps  domain.Posts
+	err error
+}
+
+func (h *mockHistory) SavePosts(d string, ps domain.Posts) error {
+	if h.err != nil {
+		return h.err
+	}
+	h.ps = append(h.ps, ps...)
+	return nil
+}
+
+func (h *mockHistory) FindPosts(q domain.HistoryQuery) (domain.Posts, error) {
+	return h.ps, nil
+}
+
+type mockMarks map[string]domain.Marks
+
+func (m mockMarks) FindMarks(d string) (domain.Marks, error) {
+	return m[d], nil
+}
+
+func (m mockMarks) SaveMarks(d string, ms domain.Marks) error {
+	m[d] = ms
+	return nil
+}
+
+type failingMarks struct {
+	err error
+}
+
+func (m failingMarks) FindMarks(d string) (domain.Marks, error) {
+	return domain.Marks{}, m.err
+}
+
+func (m failingMarks) SaveMarks(d string, ms domain.Marks) error {
+	return m.err
+}
+
 func assertPosts(actuals, expecteds domain.Posts) error {
 	if len(actuals) != len(expecteds) {
 		return reportUnexpected("len of posts", len(actuals), len(expecteds))
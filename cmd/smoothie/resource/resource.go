@@ -0,0 +1,6 @@
+package resource
+
+import "embed"
+
+//go:embed html
+var HTML embed.FS
@@ -1,18 +1,25 @@
 package runner
 
 import (
+	csvPkg "encoding/csv"
 	jsonPkg "encoding/json"
 	"fmt"
+	htmlPkg "html/template"
 	"io"
-	"log"
+	"io/fs"
+	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	templatePkg "text/template"
+	"time"
 
 	"github.com/buger/goterm"
 	colorPkg "github.com/fatih/color"
-	"github.com/tomocy/caster"
+	"github.com/tomocy/smoothie/cmd/smoothie/resource"
 	"github.com/tomocy/smoothie/domain"
 )
 
@@ -20,7 +27,7 @@ type text struct {
 	printed sync.Once
 }
 
-func (t *text) PrintPosts(w io.Writer, ps domain.Posts) {
+func (t *text) PrintPosts(w io.Writer, ps domain.Posts) error {
 	for _, p := range ps {
 		t.printed.Do(func() {
 			t.printVerticalLine(w)
@@ -28,6 +35,8 @@ func (t *text) PrintPosts(w io.Writer, ps domain.Posts) {
 		t.printPost(w, p)
 		t.printVerticalLine(w)
 	}
+
+	return nil
 }
 
 func (t *text) printVerticalLine(w io.Writer) {
@@ -40,25 +49,103 @@ func (t *text) printPost(w io.Writer, p *domain.Post) {
 	if p.User.Username != "" {
 		fmt.Fprintf(w, " @%s", p.User.Username)
 	}
-	fmt.Fprintf(w, " %s\n%s\n", p.CreatedAt.Format("2006/01/02 15:04"), p.Text)
+	fmt.Fprintf(w, " %s\n", p.CreatedAt.Format("2006/01/02 15:04"))
+	if heading := joinHeading(p); heading != "" {
+		fmt.Fprintln(w, heading)
+	}
+	if p.Text != "" {
+		fmt.Fprintln(w, p.Text)
+	}
+	for _, detail := range joinDetails(p) {
+		fmt.Fprintln(w, detail)
+	}
+}
+
+func joinHeading(p *domain.Post) string {
+	var ss []string
+	if p.Channel != "" {
+		ss = append(ss, fmt.Sprintf("[%s]", p.Channel))
+	}
+	if p.Title != "" {
+		ss = append(ss, p.Title)
+	}
+
+	return strings.Join(ss, " ")
+}
+
+func joinDetails(p *domain.Post) []string {
+	var ds []string
+	if p.ParentID != "" {
+		ds = append(ds, fmt.Sprintf("in reply to %s", p.ParentID))
+	}
+	for _, m := range p.Media {
+		ds = append(ds, fmt.Sprintf("%s: %s", m.Type, m.URL))
+	}
+	if len(p.Tags) > 0 {
+		tags := make([]string, len(p.Tags))
+		for i, t := range p.Tags {
+			tags[i] = "#" + t
+		}
+		ds = append(ds, strings.Join(tags, " "))
+	}
+	if metrics := joinMetrics(p.Metrics); metrics != "" {
+		ds = append(ds, metrics)
+	}
+	if len(p.Sources) > 1 {
+		ds = append(ds, joinSources(p.Sources))
+	}
+	if p.URL != "" {
+		ds = append(ds, p.URL)
+	}
+
+	return ds
+}
+
+func joinSources(srcs []*domain.Source) string {
+	ds := make([]string, len(srcs))
+	for i, src := range srcs {
+		ds[i] = src.Driver
+	}
+
+	return fmt.Sprintf("via %s", strings.Join(ds, ", "))
+}
+
+func joinMetrics(m domain.Metrics) string {
+	var ss []string
+	if m.Likes != 0 {
+		ss = append(ss, fmt.Sprintf("%d likes", m.Likes))
+	}
+	if m.Reposts != 0 {
+		ss = append(ss, fmt.Sprintf("%d reposts", m.Reposts))
+	}
+	if m.Replies != 0 {
+		ss = append(ss, fmt.Sprintf("%d replies", m.Replies))
+	}
+	if m.Score != 0 {
+		ss = append(ss, fmt.Sprintf("%d points", m.Score))
+	}
+
+	return strings.Join(ss, " | ")
 }
 
 var (
 	driverColors = map[string]*colorPkg.Color{
-		"github":  colorPkg.New(colorPkg.FgBlack),
-		"gmail":   colorPkg.New(colorPkg.FgRed),
-		"tumblr":  colorPkg.New(colorPkg.FgBlue),
-		"twitter": colorPkg.New(colorPkg.FgCyan),
-		"reddit":  colorPkg.New(colorPkg.FgRed),
+		"github":     colorPkg.New(colorPkg.FgBlack),
+		"gmail":      colorPkg.New(colorPkg.FgRed),
+		"tumblr":     colorPkg.New(colorPkg.FgBlue),
+		"twitter":    colorPkg.New(colorPkg.FgCyan),
+		"reddit":     colorPkg.New(colorPkg.FgRed),
+		"mastodon":   colorPkg.New(colorPkg.FgMagenta),
+		"hackernews": colorPkg.New(colorPkg.FgYellow),
 	}
 )
 
 type color struct {
-	printed, inited sync.Once
-	white           *colorPkg.Color
+	printed, inited     sync.Once
+	white, bold, detail *colorPkg.Color
 }
 
-func (c *color) PrintPosts(w io.Writer, ps domain.Posts) {
+func (c *color) PrintPosts(w io.Writer, ps domain.Posts) error {
 	for _, p := range ps {
 		c.printed.Do(func() {
 			c.printVerticalLine(w)
@@ -66,6 +153,8 @@ func (c *color) PrintPosts(w io.Writer, ps domain.Posts) {
 		c.printPost(w, p)
 		c.printVerticalLine(w)
 	}
+
+	return nil
 }
 
 func (c *color) printVerticalLine(w io.Writer) {
@@ -86,60 +175,274 @@ func (c *color) printPost(w io.Writer, p *domain.Post) {
 	if p.User.Username != "" {
 		c.white.Fprintf(w, " @%s", p.User.Username)
 	}
-	c.white.Fprintf(w, " %s\n%s\n", p.CreatedAt.Format("2006/01/02 15:04"), p.Text)
+	c.white.Fprintf(w, " %s\n", p.CreatedAt.Format("2006/01/02 15:04"))
+	if heading := joinHeading(p); heading != "" {
+		c.bold.Fprintln(w, heading)
+	}
+	if p.Text != "" {
+		c.white.Fprintln(w, p.Text)
+	}
+	for _, detail := range joinDetails(p) {
+		c.detail.Fprintln(w, detail)
+	}
 }
 
 func (c *color) init() {
 	c.white = colorPkg.New(colorPkg.FgWhite)
+	c.bold = colorPkg.New(colorPkg.FgWhite, colorPkg.Bold)
+	c.detail = colorPkg.New(colorPkg.FgHiBlack)
 }
 
 type html struct {
+	dir    string
 	inited sync.Once
-	caster caster.Caster
+	tmpl   *htmlPkg.Template
+	err    error
 }
 
-func (h *html) PrintPosts(w io.Writer, ps domain.Posts) {
+func (h *html) PrintPosts(w io.Writer, ps domain.Posts) error {
 	h.inited.Do(h.init)
-	h.caster.Cast(w, "post.index", map[string]interface{}{
+	if h.err != nil {
+		return h.err
+	}
+
+	return h.tmpl.ExecuteTemplate(w, "master", map[string]interface{}{
+		"Posts": ps,
+	})
+}
+
+func (h *html) PrintPostItems(w io.Writer, ps domain.Posts) error {
+	h.inited.Do(h.init)
+	if h.err != nil {
+		return h.err
+	}
+
+	return h.tmpl.ExecuteTemplate(w, "posts", map[string]interface{}{
 		"Posts": ps,
 	})
 }
 
 func (h *html) init() {
-	if err := h.initCaster(); err != nil {
-		log.Fatalf("failed for html to init caster: %s\n", err)
+	h.tmpl = htmlPkg.New("")
+	for _, name := range []string{"master.html", "posts/index.html"} {
+		src, err := h.readTemplate(name)
+		if err != nil {
+			h.err = fmt.Errorf("failed to read %s: %s", name, err)
+			return
+		}
+		if _, err := h.tmpl.New(name).Parse(string(src)); err != nil {
+			h.err = fmt.Errorf("failed to parse %s: %s", name, err)
+			return
+		}
 	}
 }
 
-func (h *html) initCaster() error {
-	var err error
-	h.caster, err = caster.New(&caster.TemplateSet{
-		Filenames: []string{joinHTML("master.html")},
+func (h *html) readTemplate(name string) ([]byte, error) {
+	if h.dir != "" {
+		src, err := ioutil.ReadFile(filepath.Join(h.dir, filepath.FromSlash(name)))
+		if err == nil {
+			return src, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return fs.ReadFile(resource.HTML, path.Join("html", name))
+}
+
+type json struct{}
+
+func (j *json) PrintPosts(w io.Writer, ps domain.Posts) error {
+	return jsonPkg.NewEncoder(w).Encode(ps)
+}
+
+type ndjson struct{}
+
+func (j *ndjson) PrintPosts(w io.Writer, ps domain.Posts) error {
+	enc := jsonPkg.NewEncoder(w)
+	for _, p := range ps {
+		if err := enc.Encode(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type csv struct {
+	printed sync.Once
+}
+
+func (c *csv) PrintPosts(w io.Writer, ps domain.Posts) error {
+	csvW := csvPkg.NewWriter(w)
+	c.printed.Do(func() {
+		csvW.Write([]string{
+			"driver", "id", "user", "username", "channel", "title", "text", "url", "tags",
+			"likes", "reposts", "replies", "score", "created_at",
+		})
 	})
-	if err != nil {
-		return err
+	for _, p := range ps {
+		csvW.Write([]string{
+			p.Driver, p.ID, userNameOf(p), p.User.Username, p.Channel, p.Title, p.Text, p.URL, strings.Join(p.Tags, " "),
+			strconv.Itoa(p.Metrics.Likes), strconv.Itoa(p.Metrics.Reposts), strconv.Itoa(p.Metrics.Replies), strconv.Itoa(p.Metrics.Score),
+			p.CreatedAt.Format(time.RFC3339),
+		})
 	}
-	if err := h.caster.Extend("post.index", &caster.TemplateSet{
-		Filenames: []string{joinHTML("posts/index.html")},
-	}); err != nil {
-		return err
+	csvW.Flush()
+
+	return csvW.Error()
+}
+
+func driverNameOf(p *domain.Post) string {
+	fields := strings.Fields(p.Driver)
+	if len(fields) <= 0 {
+		return p.Driver
+	}
+
+	return fields[0]
+}
+
+func userNameOf(p *domain.Post) string {
+	if p.User.Name != "" {
+		return p.User.Name
+	}
+
+	return p.User.Username
+}
+
+type markdown struct{}
+
+func (m *markdown) PrintPosts(w io.Writer, ps domain.Posts) error {
+	for _, p := range ps {
+		if err := m.printPost(w, p); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func joinHTML(name string) string {
-	dir := joinResource("html")
-	return filepath.Join(dir, name)
+func (m *markdown) printPost(w io.Writer, p *domain.Post) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#### (%s) %s", p.Driver, p.User.Name)
+	if p.User.Username != "" {
+		fmt.Fprintf(&b, " @%s", p.User.Username)
+	}
+	fmt.Fprintf(&b, " %s\n\n", p.CreatedAt.Format("2006/01/02 15:04"))
+	if heading := joinHeading(p); heading != "" {
+		if p.URL != "" {
+			heading = fmt.Sprintf("[%s](%s)", heading, p.URL)
+		}
+		fmt.Fprintf(&b, "**%s**\n\n", heading)
+	}
+	if p.Text != "" {
+		fmt.Fprintf(&b, "%s\n\n", p.Text)
+	}
+	if details := joinDetails(p); len(details) > 0 {
+		for _, detail := range details {
+			fmt.Fprintf(&b, "- %s\n", detail)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("---\n\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
 }
 
-func joinResource(name string) string {
-	dir := filepath.Join(os.Getenv("GOPATH"), "/src/github.com/tomocy/smoothie/cmd/smoothie/resource")
-	return filepath.Join(dir, name)
+type template struct {
+	filename, text string
+	inited         sync.Once
+	tmpl           *templatePkg.Template
+	err            error
 }
 
-type json struct{}
+func (t *template) PrintPosts(w io.Writer, ps domain.Posts) error {
+	t.inited.Do(t.init)
+	if t.err != nil {
+		return t.err
+	}
+
+	for _, p := range ps {
+		var b strings.Builder
+		if err := t.tmpl.Execute(&b, p); err != nil {
+			return err
+		}
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *template) init() {
+	text := t.text
+	if text == "" && t.filename != "" {
+		src, err := ioutil.ReadFile(t.filename)
+		if err != nil {
+			t.err = fmt.Errorf("failed to read %s: %s", t.filename, err)
+			return
+		}
+		text = string(src)
+	}
+	if text == "" {
+		t.err = fmt.Errorf("no template to print posts with")
+		return
+	}
+
+	t.tmpl, t.err = templatePkg.New("post").Funcs(templatePkg.FuncMap{
+		"truncate": func(n int, s string) string {
+			return truncate(s, n)
+		},
+		"oneline": func(s string) string {
+			return strings.Join(strings.Fields(s), " ")
+		},
+		"ago":   ago,
+		"color": colorize,
+	}).Parse(text)
+}
+
+func ago(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", d/time.Minute)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dd ago", d/(24*time.Hour))
+	}
+}
+
+var (
+	namedColors = map[string]*colorPkg.Color{
+		"black":   colorPkg.New(colorPkg.FgBlack),
+		"red":     colorPkg.New(colorPkg.FgRed),
+		"green":   colorPkg.New(colorPkg.FgGreen),
+		"yellow":  colorPkg.New(colorPkg.FgYellow),
+		"blue":    colorPkg.New(colorPkg.FgBlue),
+		"magenta": colorPkg.New(colorPkg.FgMagenta),
+		"cyan":    colorPkg.New(colorPkg.FgCyan),
+		"white":   colorPkg.New(colorPkg.FgWhite),
+		"gray":    colorPkg.New(colorPkg.FgHiBlack),
+		"bold":    colorPkg.New(colorPkg.Bold),
+	}
+)
+
+func colorize(name, s string) string {
+	if col, ok := namedColors[name]; ok {
+		return col.Sprint(s)
+	}
+	if col, ok := driverColors[strings.SplitN(name, " ", 2)[0]]; ok {
+		return col.Sprint(s)
+	}
 
-func (j *json) PrintPosts(w io.Writer, ps domain.Posts) {
-	jsonPkg.NewEncoder(w).Encode(ps)
+	return s
 }
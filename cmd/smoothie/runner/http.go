@@ -0,0 +1,535 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	jsonPkg "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	httpPkg "net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tomocy/smoothie/app"
+	"github.com/tomocy/smoothie/domain"
+)
+
+type http struct {
+	cnf      config
+	printers map[string]printer
+	mu       sync.RWMutex
+	posts    domain.Posts
+	subs     map[chan domain.Posts]bool
+	streamed bool
+}
+
+func (h *http) fetchPosts(ctx context.Context) error {
+	c := &cli{cnf: h.cnf}
+	u := newPostUsecase(h.cnf)
+	ds, err := c.authorizeDrivers(ctx, u, c.parseDrivers(h.cnf.drivers))
+	if err != nil {
+		return err
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, h.cnf.timeout)
+	ps, err := u.FetchPostsOfDrivers(fetchCtx, ds...)
+	cancel()
+	if err != nil {
+		errs, ok := err.(app.DriverErrors)
+		if !ok || len(errs) >= len(ds) {
+			return err
+		}
+		c.showDriverErrors(err)
+	}
+	h.addPosts(ps)
+
+	return h.listenAndServe(ctx)
+}
+
+func (h *http) streamPosts(ctx context.Context) error {
+	c := &cli{cnf: h.cnf}
+	u := newPostUsecase(h.cnf)
+	ds, err := c.authorizeDrivers(ctx, u, c.parseDrivers(h.cnf.drivers))
+	if err != nil {
+		return err
+	}
+
+	psCh, errCh := u.StreamPostsOfDrivers(ctx, ds...)
+	h.subs, h.streamed = make(map[chan domain.Posts]bool), true
+	go h.keepPosts(psCh, errCh)
+
+	return h.listenAndServe(ctx)
+}
+
+func (h *http) keptPosts() domain.Posts {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	kepts := make(domain.Posts, len(h.posts))
+	copy(kepts, h.posts)
+
+	return kepts
+}
+
+func (h *http) addPosts(ps domain.Posts) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.posts = append(append(domain.Posts{}, ps...), h.posts...)
+	h.posts.SortByNewest()
+	if len(h.posts) > httpMaxPosts {
+		h.posts = h.posts[:httpMaxPosts]
+	}
+	h.publish(ps)
+}
+
+func (h *http) keepPosts(psCh <-chan domain.Posts, errCh <-chan error) {
+	defer h.unsubscribeAll()
+	for psCh != nil || errCh != nil {
+		select {
+		case ps, ok := <-psCh:
+			if !ok {
+				psCh = nil
+				continue
+			}
+			h.addPosts(ps)
+		case err, ok := <-errCh:
+			if !ok {
+				errCh = nil
+				continue
+			}
+			if err != context.Canceled {
+				warn(err)
+			}
+		}
+	}
+}
+
+const httpMaxPosts = 1000
+
+func (h *http) subscribe() (<-chan domain.Posts, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan domain.Posts, httpSubBuffer)
+	if h.subs == nil {
+		close(ch)
+		return ch, func() {}
+	}
+	h.subs[ch] = true
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.subs[ch] {
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+func (h *http) publish(ps domain.Posts) {
+	for ch := range h.subs {
+		select {
+		case ch <- ps:
+		default:
+		}
+	}
+}
+
+func (h *http) unsubscribeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		close(ch)
+	}
+	h.subs = nil
+}
+
+const httpSubBuffer = 16
+
+func (h *http) listenAndServe(ctx context.Context) error {
+	srv := &httpPkg.Server{
+		Addr:    h.cnf.addr,
+		Handler: h.handler(),
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		fmt.Printf("listen and serve on %s\n", h.cnf.addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		h.unsubscribeAll()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+func (h *http) handler() httpPkg.Handler {
+	h.printers = make(map[string]printer)
+	for _, format := range []string{formatHTML, formatJSON} {
+		cnf := h.cnf
+		cnf.format = format
+		h.printers[format] = newPrinter(cnf)
+	}
+	mux := httpPkg.NewServeMux()
+	mux.Handle("/", h.handlePosts(func(r *httpPkg.Request) (string, string, bool) {
+		switch r.URL.Path {
+		case "/":
+			return "", formatHTML, true
+		case "/posts.json":
+			return "", formatJSON, true
+		default:
+			return "", "", false
+		}
+	}))
+	mux.Handle("/drivers/", h.handlePosts(func(r *httpPkg.Request) (string, string, bool) {
+		name := strings.TrimPrefix(r.URL.Path, "/drivers/")
+		format := formatHTML
+		if strings.HasSuffix(name, ".json") {
+			name, format = strings.TrimSuffix(name, ".json"), formatJSON
+		}
+		if name == "" || strings.Contains(name, "/") {
+			return "", "", false
+		}
+
+		return name, format, true
+	}))
+	if h.streamed {
+		mux.Handle("/events", h.handleEvents())
+	}
+	mux.Handle("/api/posts", h.handleAPIPosts())
+	mux.Handle("/api/drivers", h.handleAPIDrivers())
+
+	return mux
+}
+
+func (h *http) handlePosts(route func(*httpPkg.Request) (string, string, bool)) httpPkg.Handler {
+	return httpPkg.HandlerFunc(func(w httpPkg.ResponseWriter, r *httpPkg.Request) {
+		if r.Method != httpPkg.MethodGet {
+			w.WriteHeader(httpPkg.StatusMethodNotAllowed)
+			return
+		}
+		driver, format, ok := route(r)
+		if !ok {
+			httpPkg.NotFound(w, r)
+			return
+		}
+
+		ps := h.keptPosts()
+		if driver != "" {
+			ps = postsOfDriver(ps, driver)
+		}
+
+		var b bytes.Buffer
+		if err := h.printers[format].PrintPosts(&b, ps); err != nil {
+			httpPkg.Error(w, err.Error(), httpPkg.StatusInternalServerError)
+			return
+		}
+		switch format {
+		case formatJSON:
+			w.Header().Set("Content-Type", "application/json")
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		}
+		b.WriteTo(w)
+	})
+}
+
+func postsOfDriver(ps domain.Posts, name string) domain.Posts {
+	filtereds := make(domain.Posts, 0, len(ps))
+	for _, p := range ps {
+		if driverNameOf(p) == name {
+			filtereds = append(filtereds, p)
+		}
+	}
+
+	return filtereds
+}
+
+func (h *http) handleEvents() httpPkg.Handler {
+	return httpPkg.HandlerFunc(func(w httpPkg.ResponseWriter, r *httpPkg.Request) {
+		if r.Method != httpPkg.MethodGet {
+			w.WriteHeader(httpPkg.StatusMethodNotAllowed)
+			return
+		}
+		flusher, ok := w.(httpPkg.Flusher)
+		if !ok {
+			httpPkg.Error(w, "streaming is not supported", httpPkg.StatusInternalServerError)
+			return
+		}
+		driver := r.URL.Query().Get("driver")
+		write := writeEvent
+		if r.URL.Query().Get("format") == formatHTML {
+			write = h.writeHTMLEvent
+		}
+
+		psCh, unsubscribe := h.subscribe()
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(httpPkg.StatusOK)
+		flusher.Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case ps, ok := <-psCh:
+				if !ok {
+					return
+				}
+				if driver != "" {
+					ps = postsOfDriver(ps, driver)
+				}
+				if len(ps) <= 0 {
+					continue
+				}
+				if err := write(w, ps); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+func writeEvent(w io.Writer, ps domain.Posts) error {
+	data, err := jsonPkg.Marshal(ps)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: posts\ndata: %s\n\n", data)
+
+	return err
+}
+
+func (h *http) writeHTMLEvent(w io.Writer, ps domain.Posts) error {
+	var b bytes.Buffer
+	if err := h.printers[formatHTML].(*html).PrintPostItems(&b, ps); err != nil {
+		warn(fmt.Errorf("failed to render posts: %s", err))
+		return nil
+	}
+
+	if _, err := fmt.Fprint(w, "event: posts\n"); err != nil {
+		return err
+	}
+	rendered := strings.ReplaceAll(strings.TrimSpace(b.String()), "\r", "")
+	for _, line := range strings.Split(rendered, "\n") {
+		if _, err := fmt.Fprintf(w, "data: %s\n", line); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprint(w, "\n")
+
+	return err
+}
+
+func (h *http) handleAPIPosts() httpPkg.Handler {
+	return httpPkg.HandlerFunc(func(w httpPkg.ResponseWriter, r *httpPkg.Request) {
+		if r.Method != httpPkg.MethodGet {
+			writeAPIError(w, httpPkg.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		q, err := parseAPIPostsQuery(r)
+		if err != nil {
+			writeAPIError(w, httpPkg.StatusBadRequest, err)
+			return
+		}
+
+		writeAPI(w, q.page(h.keptPosts()))
+	})
+}
+
+type apiPostsQuery struct {
+	driver string
+	since  time.Time
+	limit  int
+	cursor *apiCursor
+}
+
+func parseAPIPostsQuery(r *httpPkg.Request) (apiPostsQuery, error) {
+	vs := r.URL.Query()
+	q := apiPostsQuery{
+		driver: vs.Get("driver"),
+		limit:  apiDefaultLimit,
+	}
+	if s := vs.Get("since"); s != "" {
+		since := new(pointOfTime)
+		if err := since.Set(s); err != nil {
+			return apiPostsQuery{}, err
+		}
+		q.since = since.Time
+	}
+	if s := vs.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			return apiPostsQuery{}, fmt.Errorf("invalid limit: %s: the limit should be a positive number", s)
+		}
+		if limit > httpMaxPosts {
+			limit = httpMaxPosts
+		}
+		q.limit = limit
+	}
+	if s := vs.Get("cursor"); s != "" {
+		cursor, err := parseAPICursor(s)
+		if err != nil {
+			return apiPostsQuery{}, err
+		}
+		q.cursor = cursor
+	}
+
+	return q, nil
+}
+
+const apiDefaultLimit = 50
+
+func (q apiPostsQuery) page(ps domain.Posts) apiPosts {
+	ordered := make(domain.Posts, len(ps))
+	copy(ordered, ps)
+	ordered.SortByNewest()
+
+	paged := apiPosts{
+		Posts: make(domain.Posts, 0, q.limit),
+	}
+	for _, p := range q.cursor.after(ordered) {
+		if q.driver != "" && driverNameOf(p) != q.driver {
+			continue
+		}
+		if !q.since.IsZero() && p.CreatedAt.Before(q.since) {
+			break
+		}
+		if len(paged.Posts) >= q.limit {
+			paged.Next = cursorOf(paged.Posts[len(paged.Posts)-1]).String()
+			break
+		}
+		paged.Posts = append(paged.Posts, p)
+	}
+
+	return paged
+}
+
+type apiPosts struct {
+	Posts domain.Posts
+	Next  string `json:",omitempty"`
+}
+
+type apiCursor struct {
+	createdAt time.Time
+	key       string
+}
+
+func cursorOf(p *domain.Post) *apiCursor {
+	return &apiCursor{
+		createdAt: p.CreatedAt,
+		key:       keyOf(p),
+	}
+}
+
+func parseAPICursor(s string) (*apiCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %s", s)
+	}
+	splited := strings.SplitN(string(decoded), ":", 2)
+	if len(splited) != 2 {
+		return nil, fmt.Errorf("invalid cursor: %s", s)
+	}
+	nsec, err := strconv.ParseInt(splited[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %s", s)
+	}
+
+	return &apiCursor{
+		createdAt: time.Unix(0, nsec),
+		key:       splited[1],
+	}, nil
+}
+
+func (c *apiCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", c.createdAt.UnixNano(), c.key)))
+}
+
+func (c *apiCursor) after(ps domain.Posts) domain.Posts {
+	if c == nil {
+		return ps
+	}
+	for i, p := range ps {
+		if keyOf(p) == c.key {
+			return ps[i+1:]
+		}
+	}
+	for i, p := range ps {
+		if p.CreatedAt.Before(c.createdAt) {
+			return ps[i:]
+		}
+	}
+
+	return nil
+}
+
+func keyOf(p *domain.Post) string {
+	return p.DriverKey + ":" + p.ID
+}
+
+func (h *http) handleAPIDrivers() httpPkg.Handler {
+	return httpPkg.HandlerFunc(func(w httpPkg.ResponseWriter, r *httpPkg.Request) {
+		if r.Method != httpPkg.MethodGet {
+			writeAPIError(w, httpPkg.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+
+		ds := (&cli{cnf: h.cnf}).parseDrivers(h.cnf.drivers)
+		adapteds := make([]*apiDriver, len(ds))
+		for i, d := range ds {
+			adapteds[i] = &apiDriver{
+				Driver: d.String(), Name: d.Name, Args: d.Args,
+			}
+			if d.Interval != 0 {
+				adapteds[i].Interval = d.Interval.String()
+			}
+		}
+
+		writeAPI(w, apiDrivers{
+			Drivers: adapteds,
+		})
+	})
+}
+
+type apiDrivers struct {
+	Drivers []*apiDriver
+}
+
+type apiDriver struct {
+	Driver   string
+	Name     string
+	Args     []string `json:",omitempty"`
+	Interval string   `json:",omitempty"`
+}
+
+func writeAPI(w httpPkg.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	jsonPkg.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w httpPkg.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	jsonPkg.NewEncoder(w).Encode(apiError{
+		Error: err.Error(),
+	})
+}
+
+type apiError struct {
+	Error string
+}
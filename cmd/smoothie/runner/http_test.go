@@ -0,0 +1,205 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	httpPkg "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+)
+
+func TestAPICursor(t *testing.T) {
+	p := &domain.Post{ID: "1", Driver: "github", DriverKey: "github:issues:golang/go", CreatedAt: time.Date(2019, 10, 1, 9, 0, 0, 123, time.UTC)}
+	cursor := cursorOf(p)
+	parsed, err := parseAPICursor(cursor.String())
+	if err != nil {
+		t.Fatalf("unexpected error by parseAPICursor: got %s, expect <nil>\n", err)
+	}
+	if !parsed.createdAt.Equal(p.CreatedAt) {
+		t.Errorf("unexpected created at of cursor by parseAPICursor: got %s, expect %s\n", parsed.createdAt, p.CreatedAt)
+	}
+	if parsed.key != keyOf(p) {
+		t.Errorf("unexpected key of cursor by parseAPICursor: got %s, expect %s\n", parsed.key, keyOf(p))
+	}
+
+	other := &domain.Post{ID: "1", Driver: "github", DriverKey: "github:issues:tomocy/smoothie", CreatedAt: p.CreatedAt}
+	if keyOf(other) == keyOf(p) {
+		t.Errorf("unexpected key of post of other driver by keyOf: got %s, expect other than %s\n", keyOf(other), keyOf(p))
+	}
+
+	for _, s := range []string{"!", "MTIz", "YWJjOjE"} {
+		if _, err := parseAPICursor(s); err == nil {
+			t.Errorf("unexpected error by parseAPICursor with %s: got <nil>, expect error of invalid cursor\n", s)
+		}
+	}
+}
+
+func TestParseAPIPostsQuery(t *testing.T) {
+	tests := map[string]struct {
+		query         string
+		expectedLimit int
+		expectsErr    bool
+	}{
+		"default limit": {
+			query: "", expectedLimit: apiDefaultLimit,
+		},
+		"limit": {
+			query: "limit=20", expectedLimit: 20,
+		},
+		"too large limit": {
+			query: "limit=5000", expectedLimit: httpMaxPosts,
+		},
+		"zero limit": {
+			query: "limit=0", expectsErr: true,
+		},
+		"negative limit": {
+			query: "limit=-1", expectsErr: true,
+		},
+		"invalid limit": {
+			query: "limit=many", expectsErr: true,
+		},
+		"invalid since": {
+			query: "since=yesterday", expectsErr: true,
+		},
+		"invalid cursor": {
+			query: "cursor=!", expectsErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(httpPkg.MethodGet, "/api/posts?"+test.query, nil)
+			q, err := parseAPIPostsQuery(r)
+			if test.expectsErr {
+				if err == nil {
+					t.Errorf("unexpected error by parseAPIPostsQuery: got <nil>, expect error\n")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error by parseAPIPostsQuery: got %s, expect <nil>\n", err)
+			}
+			if q.limit != test.expectedLimit {
+				t.Errorf("unexpected limit by parseAPIPostsQuery: got %d, expect %d\n", q.limit, test.expectedLimit)
+			}
+		})
+	}
+}
+
+func TestAPIPostsQueryPage(t *testing.T) {
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	ps := domain.Posts{
+		{ID: "1", Driver: "twitter", DriverKey: "twitter", CreatedAt: now},
+		{ID: "2", Driver: "reddit", DriverKey: "reddit", CreatedAt: now.Add(-1 * time.Hour)},
+		{ID: "3", Driver: "twitter", DriverKey: "twitter", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "4", Driver: "reddit", DriverKey: "reddit", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "5", Driver: "twitter", DriverKey: "twitter", CreatedAt: now.Add(-4 * time.Hour)},
+	}
+	vanished := &domain.Post{ID: "6", Driver: "twitter", DriverKey: "twitter", CreatedAt: now.Add(-90 * time.Minute)}
+	tests := map[string]struct {
+		q            apiPostsQuery
+		expectedIDs  []string
+		expectedNext *domain.Post
+	}{
+		"first page": {
+			q:            apiPostsQuery{limit: 2},
+			expectedIDs:  []string{"1", "2"},
+			expectedNext: ps[1],
+		},
+		"next page": {
+			q:            apiPostsQuery{limit: 2, cursor: cursorOf(ps[1])},
+			expectedIDs:  []string{"3", "4"},
+			expectedNext: ps[3],
+		},
+		"last page": {
+			q:           apiPostsQuery{limit: 2, cursor: cursorOf(ps[3])},
+			expectedIDs: []string{"5"},
+		},
+		"vanished cursor": {
+			q:            apiPostsQuery{limit: 2, cursor: cursorOf(vanished)},
+			expectedIDs:  []string{"3", "4"},
+			expectedNext: ps[3],
+		},
+		"since": {
+			q:           apiPostsQuery{limit: 10, since: now.Add(-2 * time.Hour)},
+			expectedIDs: []string{"1", "2", "3"},
+		},
+		"driver": {
+			q:            apiPostsQuery{limit: 2, driver: "twitter"},
+			expectedIDs:  []string{"1", "3"},
+			expectedNext: ps[2],
+		},
+		"driver of next page": {
+			q:           apiPostsQuery{limit: 2, driver: "twitter", cursor: cursorOf(ps[2])},
+			expectedIDs: []string{"5"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			paged := test.q.page(ps)
+			if len(paged.Posts) != len(test.expectedIDs) {
+				t.Fatalf("unexpected len of posts by (apiPostsQuery).page: got %d, expect %d\n", len(paged.Posts), len(test.expectedIDs))
+			}
+			for i, id := range test.expectedIDs {
+				if paged.Posts[i].ID != id {
+					t.Errorf("unexpected id of posts[%d] by (apiPostsQuery).page: got %s, expect %s\n", i, paged.Posts[i].ID, id)
+				}
+			}
+			var expectedNext string
+			if test.expectedNext != nil {
+				expectedNext = cursorOf(test.expectedNext).String()
+			}
+			if paged.Next != expectedNext {
+				t.Errorf("unexpected next by (apiPostsQuery).page: got %s, expect %s\n", paged.Next, expectedNext)
+			}
+		})
+	}
+}
+
+func TestHTTPListenAndServeShutsDownWithEvents(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find free port: %s", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	h := &http{
+		cnf:  config{addr: addr},
+		subs: make(map[chan domain.Posts]bool), streamed: true,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.listenAndServe(ctx)
+	}()
+
+	var resp *httpPkg.Response
+	for i := 0; i < 50; i++ {
+		resp, err = httpPkg.Get(fmt.Sprintf("http://%s/events", addr))
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("failed to subscribe events: %s", err)
+	}
+	defer resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("unexpected error by (*http).listenAndServe: got %s, expect <nil>\n", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("unexpected blocking of (*http).listenAndServe with subscribed events\n")
+	}
+}
@@ -2,9 +2,7 @@ package runner
 
 import (
 	"context"
-	"flag"
 	"fmt"
-	httpPkg "net/http"
 	"os"
 	"strings"
 
@@ -13,30 +11,172 @@ import (
 )
 
 type cli struct {
+	cnf     config
 	printer printer
 }
 
-func (c *cli) fetchPosts() error {
-	ds := c.parseDrivers(flag.Args())
-	u := newPostUsecase()
-	ps, err := u.FetchPostsOfDrivers(ds...)
+func (c *cli) fetchPosts(ctx context.Context) error {
+	u := newPostUsecase(c.cnf)
+	ds, err := c.authorizeDrivers(ctx, u, c.parseDrivers(c.cnf.drivers))
 	if err != nil {
 		return err
 	}
 
-	c.ShowPosts(ps)
+	ctx, cancel := context.WithTimeout(ctx, c.cnf.timeout)
+	defer cancel()
+
+	fetch := u.FetchPostsOfDrivers
+	if c.cnf.onlyNew {
+		fetch = u.FetchUnreadPostsOfDrivers
+	}
+	ps, err := fetch(ctx, ds...)
+
+	return c.showFetchedPosts(ps, err, len(ds))
+}
+
+func (c *cli) searchPosts(ctx context.Context) error {
+	if c.cnf.query == "" {
+		return fmt.Errorf("no query to search posts for")
+	}
+
+	u := newPostUsecase(c.cnf)
+	ds, err := c.authorizeDrivers(ctx, u, c.parseDrivers(c.cnf.drivers))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cnf.timeout)
+	defer cancel()
+
+	ps, err := u.SearchPostsOfDrivers(ctx, c.cnf.query, ds...)
+
+	return c.showFetchedPosts(ps, err, len(ds))
+}
+
+func (c *cli) writePost(ctx context.Context) error {
+	if len(c.cnf.post.to) <= 0 {
+		return fmt.Errorf("no drivers to post to")
+	}
+	if c.cnf.post.text == "" {
+		return fmt.Errorf("no text to post")
+	}
+
+	ds := c.parseDrivers(c.cnf.post.to)
+	u := newPostUsecase(c.cnf)
+	if c.cnf.post.replyTo != "" {
+		if len(ds) != 1 {
+			return fmt.Errorf("only one driver can be replied with")
+		}
+		p, err := u.ReplyToPost(ctx, ds[0], c.cnf.post.replyTo, c.cnf.post.text)
+		if err != nil {
+			return err
+		}
+		c.showWrittenPost(ds[0].String(), p)
+		return nil
+	}
+
+	ps, err := u.CreatePostOfDrivers(ctx, c.cnf.post.text, ds...)
+	if err != nil {
+		errs, ok := err.(app.DriverErrors)
+		if !ok {
+			return err
+		}
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "failed to post to %s: %s\n", err.Driver, err.Err)
+		}
+		if len(errs) >= len(ds) {
+			return fmt.Errorf("failed to post to any drivers")
+		}
+	}
+	for _, p := range ps {
+		c.showWrittenPost(p.Driver, p)
+	}
+
+	return nil
+}
+
+func (c *cli) react(ctx context.Context) error {
+	i := strings.LastIndex(c.cnf.react.post, ":")
+	if i <= 0 {
+		return fmt.Errorf("invalid format of post: %s: the format should be driver:id", c.cnf.react.post)
+	}
+	if c.cnf.react.action == "" {
+		return fmt.Errorf("no action to react with")
+	}
+
+	d, id := c.parseDriver(c.cnf.react.post[:i]), c.cnf.react.post[i+1:]
+	u := newPostUsecase(c.cnf)
+	if _, err := c.authorizeDrivers(ctx, u, []app.Driver{d}); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cnf.timeout)
+	defer cancel()
+
+	if err := u.ReactToPost(ctx, d, id, c.cnf.react.action); err != nil {
+		return err
+	}
+	fmt.Printf("reacted to %s:%s with %s\n", d, id, c.cnf.react.action)
+
+	return nil
+}
+
+func (c *cli) authorizeDrivers(ctx context.Context, u *app.PostUsecase, ds []app.Driver) ([]app.Driver, error) {
+	authorizeds, err := u.AuthorizeDrivers(ctx, ds...)
+	if err != nil {
+		errs, ok := err.(app.DriverErrors)
+		if !ok || len(errs) >= len(ds) {
+			return nil, err
+		}
+		for _, err := range errs {
+			warn(fmt.Errorf("failed to authorize %s: %s", err.Driver, err.Err))
+		}
+	}
+
+	return authorizeds, nil
+}
+
+func (c *cli) showWrittenPost(d string, p *domain.Post) {
+	fmt.Printf("posted to %s: %s\n", d, p.URL)
+}
+
+func (c *cli) showFetchedPosts(ps domain.Posts, err error, n int) error {
+	if err != nil {
+		errs, ok := err.(app.DriverErrors)
+		if !ok || len(errs) >= n {
+			return err
+		}
+	}
+
+	if err := c.ShowPosts(ps); err != nil {
+		return err
+	}
+	c.showDriverErrors(err)
 
 	return nil
 }
 
+func (c *cli) showDriverErrors(err error) {
+	errs, ok := err.(app.DriverErrors)
+	if !ok {
+		return
+	}
+
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "warning: failed to fetch posts of %s: %s\n", err.Driver, err.Err)
+	}
+}
+
 func (c *cli) streamPosts(ctx context.Context) error {
-	ds := c.parseDrivers(flag.Args())
-	u := newPostUsecase()
+	ds := c.parseDrivers(c.cnf.drivers)
+	u := newPostUsecase(c.cnf)
 	psCh, errCh := u.StreamPostsOfDrivers(ctx, ds...)
 	for {
 		select {
 		case ps := <-psCh:
-			c.ShowPosts(ps)
+			if err := c.ShowPosts(ps); err != nil {
+				return err
+			}
 		case err := <-errCh:
 			if err == context.Canceled {
 				return nil
@@ -46,6 +186,27 @@ func (c *cli) streamPosts(ctx context.Context) error {
 	}
 }
 
+func (c *cli) showHistory() error {
+	q := c.cnf.history
+	q.Drivers = c.cnf.drivers
+	u := newPostUsecase(c.cnf)
+	ps, err := u.FetchHistory(q)
+	if err != nil {
+		return err
+	}
+
+	return c.ShowPosts(ps)
+}
+
+func (c *cli) markDriversAsRead() error {
+	if len(c.cnf.drivers) <= 0 {
+		return fmt.Errorf("no drivers to mark as read")
+	}
+
+	u := newPostUsecase(c.cnf)
+	return u.MarkDriversAsRead(c.parseDrivers(c.cnf.drivers)...)
+}
+
 func (c *cli) parseDrivers(ds []string) []app.Driver {
 	parseds := make([]app.Driver, len(ds))
 	for i, d := range ds {
@@ -60,41 +221,25 @@ func (c *cli) parseDriver(d string) app.Driver {
 	var name string
 	var args []string
 	switch splited[0] {
-	case "gmail", "tumblr", "twitter", "qiita", "reddit":
+	case "gmail", "tumblr", "twitter", "qiita", "reddit", "feed":
 		name, args = separateDriverAndArgs(splited, 1)
 	default:
 		name, args = separateDriverAndArgs(splited, 2)
 	}
 
-	return app.Driver{
+	parsed := app.Driver{
 		Name: name, Args: args,
 	}
+	parsed.Interval = c.cnf.intervals.of(parsed)
+
+	return parsed
 }
 
-func (c *cli) ShowPosts(ps domain.Posts) {
+func (c *cli) ShowPosts(ps domain.Posts) error {
 	ordered := orderPostsByOldest(ps)
-	c.printer.PrintPosts(os.Stdout, ordered)
+	return c.printer.PrintPosts(os.Stdout, ordered)
 }
 
 func (c *cli) ShowAuthURL(url string) {
 	fmt.Printf("open this url: %s\n", url)
 }
-
-type http struct {
-	printer printer
-}
-
-func (h *http) ShowPosts(ps domain.Posts) {
-	httpPkg.HandleFunc("/", func(w httpPkg.ResponseWriter, r *httpPkg.Request) {
-		h.printer.PrintPosts(w, ps)
-	})
-	h.listenAndServe()
-}
-
-func (h *http) listenAndServe() {
-	addr := ":80"
-	fmt.Printf("listen and serve on %s\n", addr)
-	if err := httpPkg.ListenAndServe(addr, nil); err != nil {
-		fmt.Fprintf(os.Stderr, "failed for http to listen and serve: %s\n", err)
-	}
-}
@@ -0,0 +1,100 @@
+package runner
+
+import (
+	jsonPkg "encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tomocy/smoothie/infra"
+)
+
+func loadProfile(filename, name string) (profile, error) {
+	src, err := os.Open(filename)
+	if err != nil {
+		return profile{}, err
+	}
+	defer src.Close()
+
+	var loaded profiles
+	if err := jsonPkg.NewDecoder(src).Decode(&loaded); err != nil {
+		return profile{}, fmt.Errorf("failed to decode %s: %s", filename, err)
+	}
+
+	p, ok := loaded.Profiles[name]
+	if !ok {
+		return profile{}, fmt.Errorf("unknown profile: %s", name)
+	}
+
+	return p, nil
+}
+
+func defaultProfilesFilename() string {
+	return filepath.Join(infra.WorkspaceName(), "feeds.json")
+}
+
+type profiles struct {
+	Profiles map[string]profile `json:"profiles"`
+}
+
+type profile struct {
+	Verb    string           `json:"verb"`
+	Mode    string           `json:"mode"`
+	Format  string           `json:"format"`
+	Drivers []*profileDriver `json:"drivers"`
+	Include []string         `json:"include"`
+	Exclude []string         `json:"exclude"`
+	Mutes   string           `json:"mutes"`
+}
+
+func (p profile) apply(cnf *config, explicits map[string]bool) error {
+	if p.Verb != "" && !explicits["v"] {
+		cnf.verb = p.Verb
+	}
+	if p.Mode != "" && !explicits["m"] {
+		cnf.mode = p.Mode
+	}
+	if p.Format != "" && !explicits["f"] {
+		cnf.format = p.Format
+	}
+
+	ds := make([]string, 0, len(p.Drivers)+len(cnf.drivers))
+	for _, d := range p.Drivers {
+		ds = append(ds, d.Driver)
+		if d.Interval == "" {
+			continue
+		}
+		if _, ok := cnf.intervals[d.Driver]; ok {
+			continue
+		}
+		if err := cnf.intervals.set(d.Driver, d.Interval); err != nil {
+			return err
+		}
+	}
+	cnf.drivers = append(ds, cnf.drivers...)
+
+	for _, r := range p.Include {
+		if err := cnf.includes.Set(r); err != nil {
+			return err
+		}
+	}
+	for _, r := range p.Exclude {
+		if err := cnf.excludes.Set(r); err != nil {
+			return err
+		}
+	}
+	if p.Mutes != "" {
+		mutes, err := loadRules(p.Mutes)
+		if err != nil {
+			return err
+		}
+		cnf.excludes = append(cnf.excludes, mutes...)
+	}
+
+	return nil
+}
+
+type profileDriver struct {
+	Driver   string `json:"driver"`
+	Interval string `json:"interval"`
+}
@@ -1,15 +1,19 @@
 package runner
 
 import (
+	"bufio"
 	"context"
+	jsonPkg "encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/tomocy/smoothie/app"
@@ -34,13 +38,78 @@ func New() Runner {
 	switch cnf.verb {
 	case verbFetch:
 		godotenv.Load(cnf.envFilename)
+		fetcher, err := newFetcher(cnf)
+		if err != nil {
+			return &Help{
+				err: err,
+			}
+		}
 		return &Fetch{
-			cnf: cnf, fetcher: newFetcher(cnf.mode, cnf.format),
+			cnf: cnf, fetcher: fetcher,
 		}
 	case verbStream:
 		godotenv.Load(cnf.envFilename)
+		streamer, err := newStreamer(cnf)
+		if err != nil {
+			return &Help{
+				err: err,
+			}
+		}
 		return &Stream{
-			cnf: cnf, streamer: newStreamer(cnf.mode, cnf.format),
+			cnf: cnf, streamer: streamer,
+		}
+	case verbSearch:
+		godotenv.Load(cnf.envFilename)
+		searcher, err := newSearcher(cnf)
+		if err != nil {
+			return &Help{
+				err: err,
+			}
+		}
+		return &Search{
+			cnf: cnf, searcher: searcher,
+		}
+	case verbPost:
+		godotenv.Load(cnf.envFilename)
+		writer, err := newWriter(cnf)
+		if err != nil {
+			return &Help{
+				err: err,
+			}
+		}
+		return &Post{
+			writer: writer,
+		}
+	case verbReact:
+		godotenv.Load(cnf.envFilename)
+		reactor, err := newReactor(cnf)
+		if err != nil {
+			return &Help{
+				err: err,
+			}
+		}
+		return &React{
+			cnf: cnf, reactor: reactor,
+		}
+	case verbHistory:
+		historian, err := newHistorian(cnf)
+		if err != nil {
+			return &Help{
+				err: err,
+			}
+		}
+		return &History{
+			historian: historian,
+		}
+	case verbMarkRead:
+		marker, err := newMarker(cnf)
+		if err != nil {
+			return &Help{
+				err: err,
+			}
+		}
+		return &MarkRead{
+			marker: marker,
 		}
 	case verbClean:
 		return new(Clean)
@@ -56,17 +125,296 @@ type Runner interface {
 func parseConfig() (config, error) {
 	v, m, f := flag.String("v", verbFetch, "verb"), flag.String("m", modeCLI, "name of mode"), flag.String("f", formatText, "format")
 	env := flag.String("env", "./.env", "the path to .env")
+	concurrency := flag.Int("concurrency", 4, "the number of drivers to fetch concurrently")
+	timeout := flag.Duration("timeout", 30*time.Second, "the deadline to fetch posts of all drivers after authorizing them")
+	addr := flag.String("addr", ":8080", "the address to listen and serve on in http mode")
+	tmplFilename := flag.String("template", "", "the path to file of go template to print each post with in template format")
+	tmplText := flag.String("format", "", "the go template to print each post with in template format (e.g. '{{.Driver}} {{.User.Name}}: {{.Text}}')")
+	templateDir := flag.String("template-dir", defaultTemplateDir(), "the path to directory of html templates to override the bundled ones")
+	dedupURL := flag.Bool("dedup-url", false, "whether to collapse posts linking to the same url across drivers")
+	onlyNew := flag.Bool("new", false, "whether to fetch only posts which are not marked as read")
+	is := make(intervals)
+	flag.Var(is, "interval", "the intervals to poll drivers in stream (e.g. twitter=5m,github:issues=10m)")
+	isFilename := flag.String("interval-config", "", "the path to json file of the intervals to poll drivers in stream")
+	var includes, excludes rules
+	flag.Var(&includes, "include", "the rule of posts to include (e.g. text~golang, user=tomocy, driver=reddit, tag=bug, text=~^go, age<24h)")
+	flag.Var(&excludes, "exclude", "the rule of posts to exclude (e.g. user=bot)")
+	mutesFilename := flag.String("mutes", "", "the path to file of rules of posts to exclude line by line")
+	profName := flag.String("profile", "", "the name of profile to use")
+	profsFilename := flag.String("feeds", defaultProfilesFilename(), "the path to json file of profiles")
+	q := flag.String("q", "", "the query to search posts or history for")
+	user := flag.String("user", "", "the name of user to search history for")
+	since, until := new(pointOfTime), new(pointOfTime)
+	flag.Var(since, "since", "the time to search history since (e.g. 2019-10-01, 2019-10-01T09:00:00+09:00, 24h)")
+	flag.Var(until, "until", "the time to search history until (e.g. 2019-10-01, 2019-10-01T09:00:00+09:00, 24h)")
+	to := flag.String("to", "", "the drivers to post to separated by comma (e.g. twitter,mastodon:home:mastodon.social)")
+	replyTo := flag.String("reply-to", "", "the id of post to reply to")
+	reactPost := flag.String("post", "", "the post to react to as driver:id (e.g. twitter:1234, github:issues:golang/go:1)")
+	action := flag.String("action", "", "the action to react with (like, boost, upvote, downvote, star, archive or mark-read)")
+	limit := flag.Int("limit", 0, "the max number of posts to show from history")
 	flag.Parse()
 
-	return config{
+	if *isFilename != "" {
+		loaded, err := loadIntervals(*isFilename)
+		if err != nil {
+			return config{}, err
+		}
+		for d, i := range is {
+			loaded[d] = i
+		}
+		is = loaded
+	}
+
+	if *mutesFilename != "" {
+		mutes, err := loadRules(*mutesFilename)
+		if err != nil {
+			return config{}, err
+		}
+		excludes = append(excludes, mutes...)
+	}
+
+	if *tmplFilename != "" || *tmplText != "" {
+		if !isFlagPassed("f") {
+			*f = formatTemplate
+		}
+	}
+
+	cnf := config{
 		verb: *v, mode: *m, format: *f,
-		envFilename: *env,
-	}, nil
+		envFilename: *env, addr: *addr,
+		templateDir: *templateDir,
+		template: templateConfig{
+			filename: *tmplFilename, text: *tmplText,
+		},
+		concurrency: *concurrency, timeout: *timeout,
+		onlyNew: *onlyNew, dedupURL: *dedupURL,
+		intervals: is,
+		includes:  includes, excludes: excludes,
+		history: domain.HistoryQuery{
+			User: *user, Text: *q,
+			Since: since.Time, Until: until.Time,
+			Limit: *limit,
+		},
+		drivers: flag.Args(),
+		query:   *q,
+		post: postConfig{
+			to: splitDrivers(*to), replyTo: *replyTo,
+			text: strings.Join(flag.Args(), " "),
+		},
+		react: reactConfig{
+			post: *reactPost, action: domain.Reaction(*action),
+		},
+	}
+	if *profName == "" {
+		return cnf, nil
+	}
+
+	prof, err := loadProfile(*profsFilename, *profName)
+	if err != nil {
+		return config{}, err
+	}
+	explicits := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		explicits[f.Name] = true
+	})
+	if err := prof.apply(&cnf, explicits); err != nil {
+		return config{}, err
+	}
+
+	return cnf, nil
 }
 
 type config struct {
 	verb, mode, format string
-	envFilename        string
+	envFilename, addr  string
+	templateDir        string
+	template           templateConfig
+	concurrency        int
+	timeout            time.Duration
+	onlyNew, dedupURL  bool
+	intervals          intervals
+	includes, excludes rules
+	history            domain.HistoryQuery
+	drivers            []string
+	query              string
+	post               postConfig
+	react              reactConfig
+}
+
+func defaultTemplateDir() string {
+	return filepath.Join(infra.WorkspaceName(), "templates")
+}
+
+type templateConfig struct {
+	filename, text string
+}
+
+func isFlagPassed(name string) bool {
+	var passed bool
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+
+	return passed
+}
+
+type reactConfig struct {
+	post   string
+	action domain.Reaction
+}
+
+type postConfig struct {
+	to            []string
+	replyTo, text string
+}
+
+func splitDrivers(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, ",")
+}
+
+type pointOfTime struct {
+	time.Time
+}
+
+func (t *pointOfTime) String() string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+func (t *pointOfTime) Set(s string) error {
+	if d, err := time.ParseDuration(s); err == nil {
+		t.Time = time.Now().Add(-d)
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if parsed, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid format of time: %s: the format should be RFC3339, 2006-01-02 or duration ago", s)
+}
+
+type rules []*app.Rule
+
+func loadRules(name string) (rules, error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	var loaded rules
+	scanner := bufio.NewScanner(src)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := loaded.Set(line); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return loaded, nil
+}
+
+func (rs *rules) String() string {
+	ss := make([]string, len(*rs))
+	for i, r := range *rs {
+		ss[i] = r.String()
+	}
+
+	return strings.Join(ss, ",")
+}
+
+func (rs *rules) Set(s string) error {
+	r, err := app.ParseRule(s)
+	if err != nil {
+		return err
+	}
+	*rs = append(*rs, r)
+
+	return nil
+}
+
+type intervals map[string]time.Duration
+
+func loadIntervals(name string) (intervals, error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	var raw map[string]string
+	if err := jsonPkg.NewDecoder(src).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	loaded := make(intervals)
+	for d, i := range raw {
+		if err := loaded.set(d, i); err != nil {
+			return nil, err
+		}
+	}
+
+	return loaded, nil
+}
+
+func (is intervals) String() string {
+	ss := make([]string, 0, len(is))
+	for d, i := range is {
+		ss = append(ss, fmt.Sprintf("%s=%s", d, i))
+	}
+	sort.Strings(ss)
+
+	return strings.Join(ss, ",")
+}
+
+func (is intervals) Set(s string) error {
+	for _, pair := range strings.Split(s, ",") {
+		i := strings.LastIndex(pair, "=")
+		if i < 0 {
+			return fmt.Errorf("invalid format of interval: %s: the format should be driver=duration", pair)
+		}
+		if err := is.set(pair[:i], pair[i+1:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (is intervals) set(d, i string) error {
+	parsed, err := time.ParseDuration(i)
+	if err != nil {
+		return fmt.Errorf("invalid interval of %s: %s", d, err)
+	}
+	is[d] = parsed
+
+	return nil
+}
+
+func (is intervals) of(d app.Driver) time.Duration {
+	if i, ok := is[d.String()]; ok {
+		return i
+	}
+
+	return is[d.Name]
 }
 
 func separateDriverAndArgs(splited []string, n int) (string, []string) {
@@ -78,42 +426,68 @@ func separateDriverAndArgs(splited []string, n int) (string, []string) {
 }
 
 const (
-	verbFetch  = "fetch"
-	verbStream = "stream"
-	verbClean  = "clean"
+	verbFetch    = "fetch"
+	verbStream   = "stream"
+	verbSearch   = "search"
+	verbPost     = "post"
+	verbReact    = "react"
+	verbHistory  = "history"
+	verbMarkRead = "mark-read"
+	verbClean    = "clean"
 
 	modeCLI  = "cli"
+	modeTUI  = "tui"
 	modeHTTP = "http"
 
-	formatText  = "text"
-	formatColor = "color"
-	formatHTML  = "html"
-	formatJSON  = "json"
+	formatText     = "text"
+	formatColor    = "color"
+	formatHTML     = "html"
+	formatJSON     = "json"
+	formatNDJSON   = "ndjson"
+	formatCSV      = "csv"
+	formatMarkdown = "markdown"
+	formatTemplate = "template"
 )
 
-func newFetcher(mode, format string) fetcher {
-	switch mode {
+func newFetcher(cnf config) (fetcher, error) {
+	switch cnf.mode {
 	case modeCLI:
 		return &cli{
-			printer: newPrinter(format),
-		}
+			cnf: cnf, printer: newPrinter(cnf),
+		}, nil
+	case modeTUI:
+		return &tui{
+			cnf: cnf,
+		}, nil
+	case modeHTTP:
+		return &http{
+			cnf: cnf,
+		}, nil
 	default:
-		return nil
+		return nil, fmt.Errorf("unknown mode: %s", cnf.mode)
 	}
 }
 
 type fetcher interface {
-	fetchPosts() error
+	fetchPosts(context.Context) error
 }
 
-func newStreamer(mode, format string) streamer {
-	switch mode {
+func newStreamer(cnf config) (streamer, error) {
+	switch cnf.mode {
 	case modeCLI:
 		return &cli{
-			printer: newPrinter(format),
-		}
+			cnf: cnf, printer: newPrinter(cnf),
+		}, nil
+	case modeTUI:
+		return &tui{
+			cnf: cnf,
+		}, nil
+	case modeHTTP:
+		return &http{
+			cnf: cnf,
+		}, nil
 	default:
-		return nil
+		return nil, fmt.Errorf("unknown mode: %s", cnf.mode)
 	}
 }
 
@@ -121,23 +495,110 @@ type streamer interface {
 	streamPosts(context.Context) error
 }
 
-func newPrinter(format string) printer {
-	switch format {
+func newSearcher(cnf config) (searcher, error) {
+	switch cnf.mode {
+	case modeCLI:
+		return &cli{
+			cnf: cnf, printer: newPrinter(cnf),
+		}, nil
+	default:
+		return nil, fmt.Errorf("search is not supported in %s mode", cnf.mode)
+	}
+}
+
+type searcher interface {
+	searchPosts(context.Context) error
+}
+
+func newWriter(cnf config) (writer, error) {
+	switch cnf.mode {
+	case modeCLI:
+		return &cli{
+			cnf: cnf,
+		}, nil
+	default:
+		return nil, fmt.Errorf("post is not supported in %s mode", cnf.mode)
+	}
+}
+
+type writer interface {
+	writePost(context.Context) error
+}
+
+func newReactor(cnf config) (reactor, error) {
+	switch cnf.mode {
+	case modeCLI:
+		return &cli{
+			cnf: cnf,
+		}, nil
+	default:
+		return nil, fmt.Errorf("react is not supported in %s mode", cnf.mode)
+	}
+}
+
+type reactor interface {
+	react(context.Context) error
+}
+
+func newHistorian(cnf config) (historian, error) {
+	switch cnf.mode {
+	case modeCLI:
+		return &cli{
+			cnf: cnf, printer: newPrinter(cnf),
+		}, nil
+	default:
+		return nil, fmt.Errorf("history is not supported in %s mode", cnf.mode)
+	}
+}
+
+type historian interface {
+	showHistory() error
+}
+
+func newMarker(cnf config) (marker, error) {
+	switch cnf.mode {
+	case modeCLI:
+		return &cli{
+			cnf: cnf,
+		}, nil
+	default:
+		return nil, fmt.Errorf("mark-read is not supported in %s mode", cnf.mode)
+	}
+}
+
+type marker interface {
+	markDriversAsRead() error
+}
+
+func newPrinter(cnf config) printer {
+	switch cnf.format {
 	case formatText:
 		return new(text)
 	case formatColor:
 		return new(color)
 	case formatHTML:
-		return new(html)
+		return &html{
+			dir: cnf.templateDir,
+		}
 	case formatJSON:
 		return new(json)
+	case formatNDJSON:
+		return new(ndjson)
+	case formatCSV:
+		return new(csv)
+	case formatMarkdown:
+		return new(markdown)
+	case formatTemplate:
+		return &template{
+			filename: cnf.template.filename, text: cnf.template.text,
+		}
 	default:
 		return nil
 	}
 }
 
 type printer interface {
-	PrintPosts(io.Writer, domain.Posts)
+	PrintPosts(io.Writer, domain.Posts) error
 }
 
 type Fetch struct {
@@ -146,7 +607,10 @@ type Fetch struct {
 }
 
 func (f *Fetch) Run() error {
-	return f.fetcher.fetchPosts()
+	ctx, cancel := contextWithInterrupt()
+	defer cancel()
+
+	return f.fetcher.fetchPosts(ctx)
 }
 
 type Stream struct {
@@ -155,22 +619,27 @@ type Stream struct {
 }
 
 func (s *Stream) Run() error {
+	ctx, cancel := contextWithInterrupt()
+	defer cancel()
+
+	return s.streamer.streamPosts(ctx)
+}
+
+func contextWithInterrupt() (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithCancel(context.Background())
-	sigCh := make(chan os.Signal)
-	signal.Notify(sigCh, syscall.SIGINT)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
-		defer close(sigCh)
-		for {
-			select {
-			case sig := <-sigCh:
-				cancel()
-				fmt.Println(sig)
-				return
-			}
+		defer signal.Stop(sigCh)
+		select {
+		case sig := <-sigCh:
+			cancel()
+			fmt.Println(sig)
+		case <-ctx.Done():
 		}
 	}()
 
-	return s.streamer.streamPosts(ctx)
+	return ctx, cancel
 }
 
 func orderPostsByOldest(ps domain.Posts) domain.Posts {
@@ -183,10 +652,58 @@ func orderPostsByOldest(ps domain.Posts) domain.Posts {
 	return ordered
 }
 
+type Search struct {
+	cnf      config
+	searcher searcher
+}
+
+func (s *Search) Run() error {
+	ctx, cancel := contextWithInterrupt()
+	defer cancel()
+
+	return s.searcher.searchPosts(ctx)
+}
+
+type Post struct {
+	writer writer
+}
+
+func (p *Post) Run() error {
+	return p.writer.writePost(context.Background())
+}
+
+type React struct {
+	cnf     config
+	reactor reactor
+}
+
+func (r *React) Run() error {
+	ctx, cancel := contextWithInterrupt()
+	defer cancel()
+
+	return r.reactor.react(ctx)
+}
+
+type History struct {
+	historian historian
+}
+
+func (h *History) Run() error {
+	return h.historian.showHistory()
+}
+
+type MarkRead struct {
+	marker marker
+}
+
+func (r *MarkRead) Run() error {
+	return r.marker.markDriversAsRead()
+}
+
 type Clean struct{}
 
 func (c *Clean) Run() error {
-	return os.RemoveAll(infra.WorkspaceName())
+	return infra.DeleteCredentials()
 }
 
 type Help struct {
@@ -198,10 +715,23 @@ func (h *Help) Run() error {
 	return h.err
 }
 
-func newPostUsecase() *app.PostUsecase {
+func newPostUsecase(cnf config) *app.PostUsecase {
 	rs := map[string]domain.PostRepo{
-		"github:events": new(infra.GitHubEvents),
-		"github:issues": new(infra.GitHubIssues),
+		"github:events": infra.NewGitHubEvents(
+			os.Getenv("GITHUB_TOKEN"),
+		),
+		"github:issues": infra.NewGitHubIssues(
+			os.Getenv("GITHUB_TOKEN"),
+		),
+		"github:notifications": infra.NewGitHubNotifications(
+			os.Getenv("GITHUB_TOKEN"),
+		),
+		"github:pulls": infra.NewGitHubPulls(
+			os.Getenv("GITHUB_TOKEN"),
+		),
+		"github:releases": infra.NewGitHubReleases(
+			os.Getenv("GITHUB_TOKEN"),
+		),
 		"gmail": infra.NewGmail(
 			os.Getenv("GMAIL_CLIENT_ID"), os.Getenv("GMAIL_CLIENT_SECRET"), new(cli),
 		),
@@ -211,11 +741,46 @@ func newPostUsecase() *app.PostUsecase {
 		"twitter": infra.NewTwitter(
 			os.Getenv("TWITTER_CLIENT_ID"), os.Getenv("TWITTER_CLIENT_SECRET"), new(cli),
 		),
-		"qiita": new(infra.Qiita),
+		"qiita":           new(infra.Qiita),
+		"feed":            new(infra.Feed),
+		"hackernews:top":  infra.NewHackerNews(infra.HackerNewsTop),
+		"hackernews:new":  infra.NewHackerNews(infra.HackerNewsNew),
+		"hackernews:best": infra.NewHackerNews(infra.HackerNewsBest),
+		"hackernews:ask":  infra.NewHackerNews(infra.HackerNewsAsk),
+		"hackernews:show": infra.NewHackerNews(infra.HackerNewsShow),
 		"reddit": infra.NewReddit(
 			os.Getenv("REDDIT_CLIENT_ID"), os.Getenv("REDDIT_CLIENT_SECRET"), new(cli),
 		),
+		"mastodon:home": infra.NewMastodon(
+			infra.MastodonHome, new(cli),
+		),
+		"mastodon:local": infra.NewMastodon(
+			infra.MastodonLocal, new(cli),
+		),
+		"mastodon:public": infra.NewMastodon(
+			infra.MastodonPublic, new(cli),
+		),
+		"mastodon:tag": infra.NewMastodon(
+			infra.MastodonTag, new(cli),
+		),
 	}
 
-	return app.NewPostUsecase(rs)
+	opts := []app.Option{
+		app.WithConcurrency(cnf.concurrency), app.WithWarning(warn),
+		app.WithHistory(infra.NewHistory(infra.HistoryName())), app.WithMarks(infra.NewMarkStore(infra.MarkStoreName())),
+	}
+	if len(cnf.includes) > 0 || len(cnf.excludes) > 0 {
+		opts = append(opts, app.WithFilter(&app.Filter{
+			Includes: cnf.includes, Excludes: cnf.excludes,
+		}))
+	}
+	if cnf.dedupURL {
+		opts = append(opts, app.WithURLDedup())
+	}
+
+	return app.NewPostUsecase(rs, opts...)
+}
+
+func warn(err error) {
+	fmt.Fprintf(os.Stderr, "warning: %s\n", err)
 }
@@ -0,0 +1,488 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/buger/goterm"
+	"github.com/tomocy/smoothie/app"
+	"github.com/tomocy/smoothie/domain"
+)
+
+type tui struct {
+	cnf         config
+	posts       domain.Posts
+	tabs        []string
+	tab, cursor int
+	offset      int
+	status      string
+	width       int
+	height      int
+}
+
+func (t *tui) fetchPosts(ctx context.Context) error {
+	u := newPostUsecase(t.cnf)
+	ds, err := t.authorizeDrivers(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, t.cnf.timeout)
+	defer cancel()
+	ps, err := u.FetchPostsOfDrivers(fetchCtx, ds...)
+	if err != nil {
+		t.status = err.Error()
+	}
+	t.addPosts(ps)
+
+	return t.run(ctx, nil, nil)
+}
+
+func (t *tui) streamPosts(ctx context.Context) error {
+	u := newPostUsecase(t.cnf)
+	ds, err := t.authorizeDrivers(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	psCh, errCh := u.StreamPostsOfDrivers(ctx, ds...)
+
+	return t.run(ctx, psCh, errCh)
+}
+
+func (t *tui) authorizeDrivers(ctx context.Context, u *app.PostUsecase) ([]app.Driver, error) {
+	c := &cli{cnf: t.cnf}
+	return c.authorizeDrivers(ctx, u, c.parseDrivers(t.cnf.drivers))
+}
+
+func (t *tui) run(ctx context.Context, psCh <-chan domain.Posts, errCh <-chan error) error {
+	restore, err := enterRawMode()
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %s", err)
+	}
+	defer restore()
+
+	keyCh := readKeys()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	t.render()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ps, ok := <-psCh:
+			if !ok {
+				psCh = nil
+				continue
+			}
+			t.addPosts(ps)
+		case err, ok := <-errCh:
+			if !ok {
+				errCh = nil
+				continue
+			}
+			if err == context.Canceled {
+				return nil
+			}
+			t.status = err.Error()
+		case k := <-keyCh:
+			if k == keyQuit {
+				return nil
+			}
+			t.handleKey(k)
+		case <-ticker.C:
+			if goterm.Width() == t.width && goterm.Height() == t.height {
+				continue
+			}
+		}
+
+		t.render()
+	}
+}
+
+func (t *tui) addPosts(ps domain.Posts) {
+	if len(ps) <= 0 {
+		return
+	}
+
+	var selected *domain.Post
+	if visibles := t.visiblePosts(); t.cursor < len(visibles) {
+		selected = visibles[t.cursor]
+	}
+
+	t.posts = append(append(domain.Posts{}, ps...), t.posts...)
+	t.posts.SortByNewest()
+	if len(t.posts) > tuiMaxPosts {
+		t.posts = t.posts[:tuiMaxPosts]
+	}
+	for _, p := range ps {
+		t.addTab(driverNameOf(p))
+	}
+
+	if selected == nil {
+		return
+	}
+	for i, p := range t.visiblePosts() {
+		if p == selected {
+			t.offset += i - t.cursor
+			t.cursor = i
+			return
+		}
+	}
+}
+
+const tuiMaxPosts = 1000
+
+func (t *tui) addTab(name string) {
+	if len(t.tabs) <= 0 {
+		t.tabs = []string{"all"}
+	}
+	for _, tab := range t.tabs {
+		if tab == name {
+			return
+		}
+	}
+
+	t.tabs = append(t.tabs, name)
+}
+
+func (t *tui) visiblePosts() domain.Posts {
+	if t.tab <= 0 || len(t.tabs) <= t.tab {
+		return t.posts
+	}
+
+	var visibles domain.Posts
+	for _, p := range t.posts {
+		if driverNameOf(p) == t.tabs[t.tab] {
+			visibles = append(visibles, p)
+		}
+	}
+
+	return visibles
+}
+
+func (t *tui) handleKey(k key) {
+	visibles := t.visiblePosts()
+	switch k {
+	case keyDown:
+		if t.cursor < len(visibles)-1 {
+			t.cursor++
+		}
+	case keyUp:
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case keyTop:
+		t.cursor = 0
+	case keyBottom:
+		t.cursor = len(visibles) - 1
+	case keyNextTab:
+		t.switchTab(1)
+	case keyPrevTab:
+		t.switchTab(-1)
+	case keyOpen:
+		if t.cursor < len(visibles) {
+			t.open(visibles[t.cursor])
+		}
+	}
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+}
+
+func (t *tui) switchTab(delta int) {
+	if len(t.tabs) <= 0 {
+		return
+	}
+
+	t.tab = (t.tab + delta + len(t.tabs)) % len(t.tabs)
+	t.cursor, t.offset = 0, 0
+}
+
+func (t *tui) open(p *domain.Post) {
+	if p.URL == "" {
+		t.status = "no url to open"
+		return
+	}
+	if err := openBrowser(p.URL); err != nil {
+		t.status = fmt.Sprintf("failed to open %s: %s", p.URL, err)
+		return
+	}
+
+	t.status = fmt.Sprintf("opened %s", p.URL)
+}
+
+func (t *tui) render() {
+	t.width, t.height = goterm.Width(), goterm.Height()
+	if t.width <= 0 || t.height <= 0 {
+		t.width, t.height = 80, 24
+	}
+
+	detailHeight := t.height / 3
+	listHeight := t.height - detailHeight - 3
+	visibles := t.visiblePosts()
+	t.scroll(listHeight)
+
+	var lines []string
+	lines = append(lines, t.joinTabs())
+	for i := t.offset; i < t.offset+listHeight; i++ {
+		if len(visibles) <= i {
+			lines = append(lines, "")
+			continue
+		}
+		line := truncate(joinSummary(visibles[i]), t.width)
+		if i == t.cursor {
+			line = goterm.Background(goterm.Color(pad(line, t.width), goterm.BLACK), goterm.WHITE)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, strings.Repeat("-", t.width))
+	var details []string
+	if t.cursor < len(visibles) {
+		details = t.joinDetailPane(visibles[t.cursor])
+	}
+	for i := 0; i < detailHeight; i++ {
+		if i < len(details) {
+			lines = append(lines, details[i])
+			continue
+		}
+		lines = append(lines, "")
+	}
+	lines = append(lines, truncate(t.joinStatus(len(visibles)), t.width))
+
+	for i, line := range lines {
+		goterm.Print(goterm.MoveTo(line+"\033[K", 1, i+1))
+	}
+	goterm.Flush()
+}
+
+func (t *tui) scroll(height int) {
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.offset+height <= t.cursor {
+		t.offset = t.cursor - height + 1
+	}
+	if t.offset < 0 {
+		t.offset = 0
+	}
+}
+
+func (t *tui) joinTabs() string {
+	tabs := make([]string, len(t.tabs))
+	for i, tab := range t.tabs {
+		if i == t.tab {
+			tabs[i] = goterm.Bold(fmt.Sprintf("[%s]", tab))
+			continue
+		}
+		tabs[i] = fmt.Sprintf(" %s ", tab)
+	}
+
+	return strings.Join(tabs, " ")
+}
+
+func (t *tui) joinDetailPane(p *domain.Post) []string {
+	var ls []string
+	head := fmt.Sprintf("(%s) %s", p.Driver, p.User.Name)
+	if p.User.Username != "" {
+		head += fmt.Sprintf(" @%s", p.User.Username)
+	}
+	head += fmt.Sprintf(" %s", p.CreatedAt.Format("2006/01/02 15:04"))
+	ls = append(ls, wrap(head, t.width)...)
+	if heading := joinHeading(p); heading != "" {
+		for _, l := range wrap(heading, t.width) {
+			ls = append(ls, goterm.Bold(l))
+		}
+	}
+	if p.Text != "" {
+		ls = append(ls, wrap(p.Text, t.width)...)
+	}
+	for _, detail := range joinDetails(p) {
+		ls = append(ls, wrap(detail, t.width)...)
+	}
+
+	return ls
+}
+
+func (t *tui) joinStatus(n int) string {
+	status := fmt.Sprintf("j/k: move  h/l: tab  g/G: top/bottom  o: open  q: quit  %d posts", n)
+	if t.status != "" {
+		status += "  " + t.status
+	}
+
+	return status
+}
+
+func joinSummary(p *domain.Post) string {
+	summary := joinHeading(p)
+	if summary == "" {
+		summary = p.Text
+	}
+	summary = strings.Join(strings.Fields(summary), " ")
+
+	return fmt.Sprintf("%s %-10s %s: %s", p.CreatedAt.Format("01/02 15:04"), driverNameOf(p), userNameOf(p), summary)
+}
+
+func truncate(s string, width int) string {
+	rs := []rune(s)
+	if len(rs) <= width {
+		return s
+	}
+
+	return string(rs[:width])
+}
+
+func pad(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+
+	return s
+}
+
+func wrap(s string, width int) []string {
+	var wrapped []string
+	for _, line := range strings.Split(s, "\n") {
+		rs := []rune(line)
+		for len(rs) > width {
+			wrapped = append(wrapped, string(rs[:width]))
+			rs = rs[width:]
+		}
+		wrapped = append(wrapped, string(rs))
+	}
+
+	return wrapped
+}
+
+type key int
+
+const (
+	keyUnknown key = iota
+	keyUp
+	keyDown
+	keyTop
+	keyBottom
+	keyNextTab
+	keyPrevTab
+	keyOpen
+	keyQuit
+)
+
+func readKeys() <-chan key {
+	ch := make(chan key)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				ch <- keyQuit
+				return
+			}
+			for _, k := range parseKeys(buf[:n]) {
+				ch <- k
+			}
+		}
+	}()
+
+	return ch
+}
+
+func parseKeys(b []byte) []key {
+	var ks []key
+	for i := 0; i < len(b); i++ {
+		var k key
+		if b[i] == 0x1b && i+2 < len(b) && b[i+1] == '[' {
+			k = parseArrowKey(b[i+2])
+			i += 2
+		} else {
+			k = parseKey(b[i])
+		}
+		if k != keyUnknown {
+			ks = append(ks, k)
+		}
+	}
+
+	return ks
+}
+
+func parseArrowKey(b byte) key {
+	switch b {
+	case 'A':
+		return keyUp
+	case 'B':
+		return keyDown
+	case 'C':
+		return keyNextTab
+	case 'D':
+		return keyPrevTab
+	default:
+		return keyUnknown
+	}
+}
+
+func parseKey(b byte) key {
+	switch b {
+	case 'k':
+		return keyUp
+	case 'j':
+		return keyDown
+	case 'g':
+		return keyTop
+	case 'G':
+		return keyBottom
+	case 'l', '\t':
+		return keyNextTab
+	case 'h':
+		return keyPrevTab
+	case 'o', '\r':
+		return keyOpen
+	case 'q', 0x03:
+		return keyQuit
+	default:
+		return keyUnknown
+	}
+}
+
+func enterRawMode() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	fmt.Print("\033[?1049h\033[?25l")
+
+	return func() {
+		fmt.Print("\033[?25h\033[?1049l")
+		stty(strings.TrimSpace(saved))
+	}, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+
+	return string(out), err
+}
+
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+
+	return cmd.Start()
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"sort"
 	"time"
 )
@@ -21,13 +22,104 @@ func (ps *Posts) SortByNewest() {
 type Post struct {
 	ID        string
 	Driver    string
+	DriverKey string
 	User      *User
+	Channel   string
+	Title     string
 	Text      string
+	URL       string
+	Link      string
+	Media     []*Media
+	Tags      []string
+	Metrics   Metrics
+	ParentID  string
+	Sources   []*Source
 	CreatedAt time.Time
 }
 
+type Source struct {
+	Driver string
+	ID     string
+	URL    string
+}
+
+type Media struct {
+	Type string
+	URL  string
+}
+
+type Metrics struct {
+	Likes   int
+	Reposts int
+	Replies int
+	Score   int
+}
+
+func (m Metrics) IsZero() bool {
+	return m == Metrics{}
+}
+
 type User struct {
 	ID       string
 	Name     string
 	Username string
 }
+
+type Reaction string
+
+const (
+	ReactionLike     Reaction = "like"
+	ReactionBoost    Reaction = "boost"
+	ReactionUpvote   Reaction = "upvote"
+	ReactionDownvote Reaction = "downvote"
+	ReactionStar     Reaction = "star"
+	ReactionArchive  Reaction = "archive"
+	ReactionMarkRead Reaction = "mark-read"
+)
+
+type UnsupportedReactionError struct {
+	Reaction Reaction
+}
+
+func (e *UnsupportedReactionError) Error() string {
+	return fmt.Sprintf("%s is not supported", e.Reaction)
+}
+
+type Marks struct {
+	Read, Fetched Mark
+}
+
+type Mark struct {
+	ID        string
+	CreatedAt time.Time
+}
+
+func MarkOf(ps Posts) Mark {
+	var latest Mark
+	for _, p := range ps {
+		if p.CreatedAt.After(latest.CreatedAt) {
+			latest = Mark{ID: p.ID, CreatedAt: p.CreatedAt}
+		}
+	}
+
+	return latest
+}
+
+func (m Mark) IsZero() bool {
+	return m.ID == "" && m.CreatedAt.IsZero()
+}
+
+func (m Mark) HasSeen(p *Post) bool {
+	return p.CreatedAt.Before(m.CreatedAt) || p.ID == m.ID
+}
+
+func (m Mark) Unseens(ps Posts) Posts {
+	unseens := make(Posts, 0, len(ps))
+	for _, p := range ps {
+		if !m.HasSeen(p) {
+			unseens = append(unseens, p)
+		}
+	}
+
+	return unseens
+}
@@ -1,8 +1,45 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type PostRepo interface {
-	StreamPosts(context.Context, []string) (<-chan Posts, <-chan error)
-	FetchPosts([]string) (Posts, error)
+	StreamPosts(context.Context, []string, time.Duration) (<-chan Posts, <-chan error)
+	FetchPosts(context.Context, []string) (Posts, error)
+}
+
+type Authorizer interface {
+	Authorize(context.Context, []string) error
+}
+
+type Searcher interface {
+	SearchPosts(context.Context, []string, string) (Posts, error)
+}
+
+type PostWriter interface {
+	CreatePost(context.Context, []string, string) (*Post, error)
+	Reply(context.Context, []string, string, string) (*Post, error)
+}
+
+type Reactor interface {
+	React(context.Context, []string, string, Reaction) error
+}
+
+type HistoryRepo interface {
+	SavePosts(string, Posts) error
+	FindPosts(HistoryQuery) (Posts, error)
+}
+
+type HistoryQuery struct {
+	Drivers      []string
+	User, Text   string
+	Since, Until time.Time
+	Limit        int
+}
+
+type MarkRepo interface {
+	FindMarks(string) (Marks, error)
+	SaveMarks(string, Marks) error
 }
@@ -1,6 +1,6 @@
 module github.com/tomocy/smoothie
 
-go 1.12
+go 1.16
 
 require (
 	github.com/buger/goterm v0.0.0-20181115115552-c206103e1f37
@@ -9,7 +9,6 @@ require (
 	github.com/joho/godotenv v1.3.0
 	github.com/mattn/go-colorable v0.1.2 // indirect
 	github.com/mattn/go-isatty v0.0.9 // indirect
-	github.com/tomocy/caster v0.0.0-20190430043614-32005efbaf0a
 	golang.org/x/oauth2 v0.0.0-20190604053449-0f29369cfe45
 	google.golang.org/api v0.9.0
 )
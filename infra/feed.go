@@ -0,0 +1,196 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+	"github.com/tomocy/smoothie/infra/feed"
+)
+
+var feedInterval = pollInterval{def: 5 * time.Minute, min: time.Minute}
+
+type Feed struct{}
+
+func (f *Feed) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
+	parsed := f.parseArgs(args)
+	esCh, errCh := f.streamEntries(ctx, feedInterval.of(interval), parsed.url, nil)
+	ch := make(chan domain.Posts)
+	go func() {
+		defer close(ch)
+		for es := range esCh {
+			ch <- es.Adapt()
+		}
+	}()
+
+	return ch, errCh
+}
+
+func (f *Feed) streamEntries(ctx context.Context, interval time.Duration, url string, header http.Header) (<-chan feed.Entries, <-chan error) {
+	esCh, errCh := make(chan feed.Entries), make(chan error)
+	go func() {
+		defer func() {
+			close(esCh)
+			close(errCh)
+		}()
+
+		state := &feedState{
+			seens: newSeenSet(feedSeenSize),
+		}
+		f.fetchAndSendEntries(ctx, url, header, state, esCh, errCh)
+		for {
+			select {
+			case <-ctx.Done():
+				errCh <- ctx.Err()
+				return
+			case <-time.After(interval):
+				if header == nil {
+					header = make(http.Header)
+				}
+				if state.etag != "" {
+					header.Set("If-None-Match", state.etag)
+				}
+				if state.lastModified != "" {
+					header.Set("If-Modified-Since", state.lastModified)
+				}
+				f.fetchAndSendEntries(ctx, url, header, state, esCh, errCh)
+			}
+		}
+	}()
+
+	return esCh, errCh
+}
+
+const feedSeenSize = 1000
+
+func (f *Feed) fetchAndSendEntries(ctx context.Context, url string, header http.Header, state *feedState, esCh chan<- feed.Entries, errCh chan<- error) {
+	es, cond, err := f.fetchEntries(ctx, url, header)
+	if err != nil {
+		errCh <- err
+		return
+	}
+	if cond.isZero() && len(es) <= 0 {
+		return
+	}
+
+	state.etag, state.lastModified = cond.etag, cond.lastModified
+	newers := state.newers(es)
+	if len(newers) <= 0 {
+		return
+	}
+
+	esCh <- newers
+}
+
+func (f *Feed) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	parsed := f.parseArgs(args)
+	es, _, err := f.fetchEntries(ctx, parsed.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch posts: %s", err)
+	}
+
+	return es.Adapt(), nil
+}
+
+func (f *Feed) fetchEntries(ctx context.Context, url string, header http.Header) (feed.Entries, feedCondition, error) {
+	if url == "" {
+		return nil, feedCondition{}, errors.New("url of feed is not specified")
+	}
+
+	resp, err := (&req{
+		method: http.MethodGet, url: url, header: header,
+	}).do(ctx)
+	if err != nil {
+		return nil, feedCondition{}, err
+	}
+	defer resp.Body.Close()
+
+	if http.StatusBadRequest <= resp.StatusCode {
+		return nil, feedCondition{}, errors.New(resp.Status)
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, feedCondition{}, nil
+	}
+
+	src, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, feedCondition{}, err
+	}
+	es, err := feed.Parse(src)
+	if err != nil {
+		return nil, feedCondition{}, err
+	}
+
+	return es, feedCondition{
+		etag: resp.Header.Get("ETag"), lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
+
+func (f *Feed) parseArgs(args []string) feedArgs {
+	var parsed feedArgs
+	parsed.parse(args)
+
+	return parsed
+}
+
+type feedArgs struct {
+	url string
+}
+
+func (as *feedArgs) parse(args []string) {
+	if len(args) <= 0 {
+		return
+	}
+
+	as.url = strings.Join(args, ":")
+}
+
+type feedCondition struct {
+	etag, lastModified string
+}
+
+func (c feedCondition) isZero() bool {
+	return c.etag == "" && c.lastModified == ""
+}
+
+type feedState struct {
+	feedCondition
+	createdAt time.Time
+	seens     *seenSet
+}
+
+func (s *feedState) newers(es feed.Entries) feed.Entries {
+	var newers feed.Entries
+	for _, e := range es {
+		key := feedEntryKey(e)
+		if s.seens.has(key) {
+			continue
+		}
+		s.seens.add(key)
+		if !e.CreatedAt.IsZero() && !e.CreatedAt.After(s.createdAt) {
+			continue
+		}
+		newers = append(newers, e)
+	}
+	if latest := newers.Latest(); latest.After(s.createdAt) {
+		s.createdAt = latest
+	}
+
+	return newers
+}
+
+func feedEntryKey(e *feed.Entry) string {
+	if e.ID != "" {
+		return e.ID
+	}
+	if e.Link != "" {
+		return e.Link
+	}
+
+	return e.Title
+}
@@ -0,0 +1,286 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+	"github.com/tomocy/smoothie/infra/markup"
+)
+
+func Parse(src []byte) (Entries, error) {
+	trimmed := bytes.TrimSpace(src)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		var f JSONFeed
+		if err := json.Unmarshal(trimmed, &f); err != nil {
+			return nil, err
+		}
+
+		return f.Entries(), nil
+	}
+
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(trimmed, &root); err != nil {
+		return nil, err
+	}
+	switch root.XMLName.Local {
+	case "rss":
+		var f RSS
+		if err := xml.Unmarshal(trimmed, &f); err != nil {
+			return nil, err
+		}
+
+		return f.Entries(), nil
+	case "feed":
+		var f Atom
+		if err := xml.Unmarshal(trimmed, &f); err != nil {
+			return nil, err
+		}
+
+		return f.Entries(), nil
+	default:
+		return nil, fmt.Errorf("unknown format of feed: %s", root.XMLName.Local)
+	}
+}
+
+type RSS struct {
+	Channel struct {
+		Title string     `xml:"title"`
+		Items []*RSSItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+func (f *RSS) Entries() Entries {
+	es := make(Entries, len(f.Channel.Items))
+	for i, item := range f.Channel.Items {
+		es[i] = item.entry(f.Channel.Title)
+	}
+
+	return es
+}
+
+type RSSItem struct {
+	GUID        string `xml:"guid"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Author      string `xml:"author"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	PubDate     string `xml:"pubDate"`
+}
+
+func (i *RSSItem) entry(feedTitle string) *Entry {
+	id := i.GUID
+	if id == "" {
+		id = i.Link
+	}
+	author := i.Creator
+	if author == "" {
+		author = i.Author
+	}
+	createdAt, _ := parseDate(i.PubDate)
+
+	return &Entry{
+		ID: id, FeedTitle: feedTitle, Author: author,
+		Title: i.Title, Summary: i.Description, Link: i.Link,
+		CreatedAt: createdAt,
+	}
+}
+
+type Atom struct {
+	Title  string       `xml:"title"`
+	Author *AtomAuthor  `xml:"author"`
+	Items  []*AtomEntry `xml:"entry"`
+}
+
+func (f *Atom) Entries() Entries {
+	es := make(Entries, len(f.Items))
+	for i, item := range f.Items {
+		es[i] = item.entry(f.Title, f.Author)
+	}
+
+	return es
+}
+
+type AtomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Author    *AtomAuthor `xml:"author"`
+	Links     []*AtomLink `xml:"link"`
+	Summary   string      `xml:"summary"`
+	Content   string      `xml:"content"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+}
+
+func (e *AtomEntry) entry(feedTitle string, feedAuthor *AtomAuthor) *Entry {
+	var author string
+	if e.Author != nil {
+		author = e.Author.Name
+	} else if feedAuthor != nil {
+		author = feedAuthor.Name
+	}
+	summary := e.Summary
+	if summary == "" {
+		summary = e.Content
+	}
+	date := e.Published
+	if date == "" {
+		date = e.Updated
+	}
+	createdAt, _ := parseDate(date)
+
+	return &Entry{
+		ID: e.ID, FeedTitle: feedTitle, Author: author,
+		Title: e.Title, Summary: summary, Link: e.link(),
+		CreatedAt: createdAt,
+	}
+}
+
+func (e *AtomEntry) link() string {
+	for _, l := range e.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return l.Href
+		}
+	}
+
+	return ""
+}
+
+type AtomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type AtomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type JSONFeed struct {
+	Title  string          `json:"title"`
+	Author *JSONFeedAuthor `json:"author"`
+	Items  []*JSONFeedItem `json:"items"`
+}
+
+func (f *JSONFeed) Entries() Entries {
+	es := make(Entries, len(f.Items))
+	for i, item := range f.Items {
+		es[i] = item.entry(f.Title, f.Author)
+	}
+
+	return es
+}
+
+type JSONFeedItem struct {
+	ID            string          `json:"id"`
+	URL           string          `json:"url"`
+	Title         string          `json:"title"`
+	ContentText   string          `json:"content_text"`
+	ContentHTML   string          `json:"content_html"`
+	Summary       string          `json:"summary"`
+	Author        *JSONFeedAuthor `json:"author"`
+	DatePublished string          `json:"date_published"`
+	DateModified  string          `json:"date_modified"`
+}
+
+func (i *JSONFeedItem) entry(feedTitle string, feedAuthor *JSONFeedAuthor) *Entry {
+	var author string
+	if i.Author != nil {
+		author = i.Author.Name
+	} else if feedAuthor != nil {
+		author = feedAuthor.Name
+	}
+	summary := i.Summary
+	if summary == "" {
+		summary = i.ContentText
+	}
+	if summary == "" {
+		summary = i.ContentHTML
+	}
+	date := i.DatePublished
+	if date == "" {
+		date = i.DateModified
+	}
+	createdAt, _ := parseDate(date)
+
+	return &Entry{
+		ID: i.ID, FeedTitle: feedTitle, Author: author,
+		Title: i.Title, Summary: summary, Link: i.URL,
+		CreatedAt: createdAt,
+	}
+}
+
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type Entries []*Entry
+
+func (es Entries) Adapt() domain.Posts {
+	adapteds := make(domain.Posts, len(es))
+	for i, e := range es {
+		adapteds[i] = e.Adapt()
+	}
+
+	return adapteds
+}
+
+func (es Entries) Latest() time.Time {
+	var latest time.Time
+	for _, e := range es {
+		if e.CreatedAt.After(latest) {
+			latest = e.CreatedAt
+		}
+	}
+
+	return latest
+}
+
+type Entry struct {
+	ID, FeedTitle, Author string
+	Title, Summary, Link  string
+	CreatedAt             time.Time
+}
+
+func (e *Entry) Adapt() *domain.Post {
+	name := e.Author
+	if name == "" {
+		name = e.FeedTitle
+	}
+
+	return &domain.Post{
+		ID:     e.ID,
+		Driver: "feed",
+		User: &domain.User{
+			Name: name,
+		},
+		Channel:   e.FeedTitle,
+		Title:     e.Title,
+		Text:      markup.StripHTML(e.Summary),
+		URL:       e.Link,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+var dateLayouts = []string{
+	time.RFC3339, time.RFC1123Z, time.RFC1123, time.RFC822Z, time.RFC822,
+	"Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST",
+}
+
+func parseDate(s string) (time.Time, error) {
+	trimmed := strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, trimmed); err == nil {
+			return parsed.Local(), nil
+		}
+	}
+
+	return time.Time{}, errors.New("unknown format of date")
+}
@@ -0,0 +1,227 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+)
+
+func TestFeedFetchPosts(t *testing.T) {
+	expectedDate := time.Date(2019, 8, 13, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		contentType, src string
+		expecteds        domain.Posts
+	}{
+		"rss": {
+			contentType: "application/rss+xml",
+			src: `<?xml version="1.0"?>
+<rss version="2.0">
+<channel>
+<title>smoothie blog</title>
+<item>
+<guid>1</guid>
+<title>one</title>
+<link>https://example.com/1</link>
+<description>&lt;p&gt;first&lt;/p&gt;</description>
+<pubDate>Tue, 13 Aug 2019 02:00:00 +0000</pubDate>
+</item>
+<item>
+<guid>2</guid>
+<title>two</title>
+<link>https://example.com/2</link>
+<pubDate>Tue, 13 Aug 2019 01:00:00 +0000</pubDate>
+</item>
+</channel>
+</rss>`,
+			expecteds: domain.Posts{
+				{ID: "1", Driver: "feed", User: &domain.User{Name: "smoothie blog"}, Title: "one", Text: "first", URL: "https://example.com/1", CreatedAt: expectedDate.Add(2 * time.Hour)},
+				{ID: "2", Driver: "feed", User: &domain.User{Name: "smoothie blog"}, Title: "two", URL: "https://example.com/2", CreatedAt: expectedDate.Add(1 * time.Hour)},
+			},
+		},
+		"atom": {
+			contentType: "application/atom+xml",
+			src: `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+<title>smoothie blog</title>
+<author><name>tomocy</name></author>
+<entry>
+<id>urn:1</id>
+<title>one</title>
+<link rel="alternate" href="https://example.com/1"/>
+<summary>first</summary>
+<published>2019-08-13T02:00:00Z</published>
+</entry>
+</feed>`,
+			expecteds: domain.Posts{
+				{ID: "urn:1", Driver: "feed", User: &domain.User{Name: "tomocy"}, Title: "one", Text: "first", URL: "https://example.com/1", CreatedAt: expectedDate.Add(2 * time.Hour)},
+			},
+		},
+		"json feed": {
+			contentType: "application/feed+json",
+			src: `{
+	"version": "https://jsonfeed.org/version/1",
+	"title": "smoothie blog",
+	"items": [
+		{"id": "1", "url": "https://example.com/1", "title": "one", "content_text": "first", "date_published": "2019-08-13T02:00:00Z"}
+	]
+}`,
+			expecteds: domain.Posts{
+				{ID: "1", Driver: "feed", User: &domain.User{Name: "smoothie blog"}, Title: "one", Text: "first", URL: "https://example.com/1", CreatedAt: expectedDate.Add(2 * time.Hour)},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", test.contentType)
+				fmt.Fprint(w, test.src)
+			}))
+			defer srv.Close()
+
+			actuals, err := new(Feed).FetchPosts(context.Background(), []string{srv.URL})
+			if err != nil {
+				t.Fatalf("unexpected error by (*Feed).FetchPosts: got %s, expect <nil>\n", err)
+			}
+			if err := assertPosts(actuals, test.expecteds); err != nil {
+				t.Errorf("unexpected posts by (*Feed).FetchPosts: %s\n", err)
+			}
+		})
+	}
+}
+
+func TestFeedFetchEntriesWithConditionalGET(t *testing.T) {
+	etag, lastModified := `"v1"`, "Tue, 13 Aug 2019 02:00:00 GMT"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag || r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		fmt.Fprint(w, `{"title": "smoothie blog", "items": [{"id": "1", "title": "one", "date_published": "2019-08-13T02:00:00Z"}]}`)
+	}))
+	defer srv.Close()
+
+	f := new(Feed)
+	es, cond, err := f.fetchEntries(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error by (*Feed).fetchEntries: got %s, expect <nil>\n", err)
+	}
+	if len(es) != 1 {
+		t.Errorf("unexpected len of entries by (*Feed).fetchEntries: got %d, expect 1\n", len(es))
+	}
+	if cond.etag != etag || cond.lastModified != lastModified {
+		t.Errorf("unexpected condition by (*Feed).fetchEntries: got %+v, expect etag %s and last modified %s\n", cond, etag, lastModified)
+	}
+
+	header := make(http.Header)
+	header.Set("If-None-Match", cond.etag)
+	es, _, err = f.fetchEntries(context.Background(), srv.URL, header)
+	if err != nil {
+		t.Fatalf("unexpected error by (*Feed).fetchEntries: got %s, expect <nil>\n", err)
+	}
+	if len(es) != 0 {
+		t.Errorf("unexpected len of entries by (*Feed).fetchEntries with If-None-Match: got %d, expect 0\n", len(es))
+	}
+}
+
+func TestFeedStreamEntriesWithoutDate(t *testing.T) {
+	var mu sync.Mutex
+	var etags []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		etags = append(etags, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v2"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, len(etags)))
+		fmt.Fprint(w, `{"title": "smoothie blog", "items": [{"id": "1", "title": "one"}, {"id": "2", "title": "two"}]}`)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	esCh, errCh := new(Feed).streamEntries(ctx, 10*time.Millisecond, srv.URL, nil)
+	var n int
+	for esCh != nil || errCh != nil {
+		select {
+		case es, ok := <-esCh:
+			if !ok {
+				esCh = nil
+				continue
+			}
+			n += len(es)
+		case err, ok := <-errCh:
+			if !ok {
+				errCh = nil
+				continue
+			}
+			if err != context.DeadlineExceeded {
+				t.Errorf("unexpected error by (*Feed).streamEntries: got %s, expect %s\n", err, context.DeadlineExceeded)
+			}
+		}
+	}
+
+	if n != 2 {
+		t.Errorf("unexpected len of streamed entries by (*Feed).streamEntries: got %d, expect 2\n", n)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(etags) < 3 || etags[1] != `"v1"` || etags[2] != `"v2"` {
+		t.Errorf("unexpected If-None-Match sent by (*Feed).streamEntries: got %q, expect the etag of the previous response\n", etags)
+	}
+}
+
+func assertPosts(actuals, expecteds domain.Posts) error {
+	if len(actuals) != len(expecteds) {
+		return reportUnexpected("len of posts", len(actuals), len(expecteds))
+	}
+	for i, expected := range expecteds {
+		if err := assertPost(actuals[i], expected); err != nil {
+			return fmt.Errorf("unexpected posts[%d]: %s", i, err)
+		}
+	}
+
+	return nil
+}
+
+func assertPost(actual, expected *domain.Post) error {
+	if actual.ID != expected.ID {
+		return reportUnexpected("id of post", actual.ID, expected.ID)
+	}
+	if actual.Driver != expected.Driver {
+		return reportUnexpected("driver of post", actual.Driver, expected.Driver)
+	}
+	if actual.User.Name != expected.User.Name {
+		return reportUnexpected("name of user of post", actual.User.Name, expected.User.Name)
+	}
+	if actual.Title != expected.Title {
+		return reportUnexpected("title of post", actual.Title, expected.Title)
+	}
+	if actual.Text != expected.Text {
+		return reportUnexpected("text of post", actual.Text, expected.Text)
+	}
+	if actual.URL != expected.URL {
+		return reportUnexpected("url of post", actual.URL, expected.URL)
+	}
+	if !actual.CreatedAt.Equal(expected.CreatedAt) {
+		return reportUnexpected("created at of post", actual.CreatedAt, expected.CreatedAt)
+	}
+
+	return nil
+}
+
+func reportUnexpected(name string, actual, expected interface{}) error {
+	return fmt.Errorf("unexpected %s: got %v, expect %v", name, actual, expected)
+}
@@ -4,23 +4,36 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/tomocy/smoothie/domain"
 	githubPkg "github.com/tomocy/smoothie/infra/github"
 )
 
+func NewGitHubEvents(token string) *GitHubEvents {
+	return &GitHubEvents{
+		github: github{
+			token: token,
+		},
+	}
+}
+
+var githubEventsInterval = pollInterval{def: time.Minute, min: time.Minute}
+
 type GitHubEvents struct {
 	github
 }
 
-func (g *GitHubEvents) StreamPosts(ctx context.Context, args []string) (<-chan domain.Posts, <-chan error) {
+func (g *GitHubEvents) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
 	parsed := g.parseArgs(args)
-	esCh, errCh := g.streamEvents(ctx, parsed.uname, nil, nil)
+	esCh, errCh := g.streamEvents(ctx, githubEventsInterval.of(interval), parsed, nil, nil)
 	ch := make(chan domain.Posts)
 	go func() {
 		defer close(ch)
@@ -32,7 +45,7 @@ func (g *GitHubEvents) StreamPosts(ctx context.Context, args []string) (<-chan d
 	return ch, errCh
 }
 
-func (g *GitHubEvents) streamEvents(ctx context.Context, uname string, header http.Header, params url.Values) (<-chan githubPkg.Events, <-chan error) {
+func (g *GitHubEvents) streamEvents(ctx context.Context, interval time.Duration, as githubEventsArgs, header http.Header, params url.Values) (<-chan githubPkg.Events, <-chan error) {
 	esCh, errCh := make(chan githubPkg.Events), make(chan error)
 	go func() {
 		defer func() {
@@ -40,31 +53,16 @@ func (g *GitHubEvents) streamEvents(ctx context.Context, uname string, header ht
 			close(errCh)
 		}()
 
-		lastETag := g.fetchAndSendEvents(uname, header, params, esCh, errCh)
-		for {
-			select {
-			case <-ctx.Done():
-				errCh <- ctx.Err()
-				return
-			case <-time.After(time.Minute):
-				if lastETag != "" {
-					if header == nil {
-						header = make(http.Header)
-					}
-					header.Set("If-None-Match", lastETag)
-				}
-				if etag := g.fetchAndSendEvents(uname, header, params, esCh, errCh); etag != "" {
-					lastETag = etag
-				}
-			}
-		}
+		g.pollWithETag(ctx, interval, header, func(header http.Header) string {
+			return g.fetchAndSendEvents(ctx, as, header, params, esCh, errCh)
+		}, errCh)
 	}()
 
 	return esCh, errCh
 }
 
-func (g *GitHubEvents) fetchAndSendEvents(uname string, header http.Header, params url.Values, esCh chan<- githubPkg.Events, errCh chan<- error) string {
-	es, etag, err := g.fetchEvents(uname, header, params)
+func (g *GitHubEvents) fetchAndSendEvents(ctx context.Context, as githubEventsArgs, header http.Header, params url.Values, esCh chan<- githubPkg.Events, errCh chan<- error) string {
+	es, etag, err := g.fetchEvents(ctx, as, header, params)
 	if err != nil {
 		errCh <- err
 		return ""
@@ -77,9 +75,9 @@ func (g *GitHubEvents) fetchAndSendEvents(uname string, header http.Header, para
 	return etag
 }
 
-func (g *GitHubEvents) FetchPosts(args []string) (domain.Posts, error) {
+func (g *GitHubEvents) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
 	parsed := g.parseArgs(args)
-	es, _, err := g.fetchEvents(parsed.uname, nil, nil)
+	es, _, err := g.fetchEvents(ctx, parsed, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -87,13 +85,13 @@ func (g *GitHubEvents) FetchPosts(args []string) (domain.Posts, error) {
 	return es.Adapt(), nil
 }
 
-func (g *GitHubEvents) fetchEvents(uname string, header http.Header, params url.Values) (githubPkg.Events, string, error) {
+func (g *GitHubEvents) fetchEvents(ctx context.Context, as githubEventsArgs, header http.Header, params url.Values) (githubPkg.Events, string, error) {
 	var es githubPkg.Events
 	dst := &resp{
 		body: &es,
 	}
-	if err := g.do(req{
-		method: http.MethodGet, url: g.endpoint("users", uname, "received_events"), header: header, params: params,
+	if err := g.do(ctx, req{
+		method: http.MethodGet, url: g.endpoint(as.path()...), header: header, params: params,
 	}, dst); err != nil {
 		return nil, "", err
 	}
@@ -109,24 +107,59 @@ func (g *GitHubEvents) parseArgs(args []string) githubEventsArgs {
 }
 
 type githubEventsArgs struct {
-	uname string
+	uname, org  string
+	owner, repo string
 }
 
 func (as *githubEventsArgs) parse(args []string) {
 	if len(args) <= 0 {
 		return
 	}
+	if len(args) <= 1 {
+		as.uname = args[0]
+		return
+	}
 
-	as.uname = args[0]
+	switch args[0] {
+	case "org":
+		as.org = args[1]
+	case "repo":
+		splited := strings.Split(args[1], "/")
+		if len(splited) == 2 {
+			as.owner = splited[0]
+			as.repo = splited[1]
+		}
+	}
 }
 
+func (as *githubEventsArgs) path() []string {
+	switch {
+	case as.org != "":
+		return []string{"orgs", as.org, "events"}
+	case as.owner != "" && as.repo != "":
+		return []string{"repos", as.owner, as.repo, "events"}
+	default:
+		return []string{"users", as.uname, "received_events"}
+	}
+}
+
+func NewGitHubIssues(token string) *GitHubIssues {
+	return &GitHubIssues{
+		github: github{
+			token: token,
+		},
+	}
+}
+
+var githubIssuesInterval = pollInterval{def: 5 * time.Minute, min: time.Minute}
+
 type GitHubIssues struct {
 	github
 }
 
-func (g *GitHubIssues) StreamPosts(ctx context.Context, args []string) (<-chan domain.Posts, <-chan error) {
+func (g *GitHubIssues) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
 	parsed := g.parseArgs(args)
-	isCh, errCh := g.streamIssues(ctx, parsed.owner, parsed.repo, nil)
+	isCh, errCh := g.streamIssues(ctx, githubIssuesInterval.of(interval), parsed.owner, parsed.repo, nil)
 	ch := make(chan domain.Posts)
 	go func() {
 		defer close(ch)
@@ -138,7 +171,7 @@ func (g *GitHubIssues) StreamPosts(ctx context.Context, args []string) (<-chan d
 	return ch, errCh
 }
 
-func (g *GitHubIssues) streamIssues(ctx context.Context, owner, repo string, params url.Values) (<-chan githubPkg.Issues, <-chan error) {
+func (g *GitHubIssues) streamIssues(ctx context.Context, interval time.Duration, owner, repo string, params url.Values) (<-chan githubPkg.Issues, <-chan error) {
 	isCh, errCh := make(chan githubPkg.Issues), make(chan error)
 	go func() {
 		defer func() {
@@ -146,20 +179,20 @@ func (g *GitHubIssues) streamIssues(ctx context.Context, owner, repo string, par
 			close(errCh)
 		}()
 
-		lastCreatedAt := g.fetchAndSendIssues(owner, repo, params, isCh, errCh)
+		lastCreatedAt := g.fetchAndSendIssues(ctx, owner, repo, params, isCh, errCh)
 		for {
 			select {
 			case <-ctx.Done():
 				errCh <- ctx.Err()
 				return
-			case <-time.After(5 * time.Minute):
+			case <-time.After(g.waitFor(interval)):
 				if !lastCreatedAt.IsZero() {
 					if params == nil {
 						params = make(url.Values)
 					}
 					params.Set("since", lastCreatedAt.Format(time.RFC3339))
 				}
-				if createdAt := g.fetchAndSendIssues(owner, repo, params, isCh, errCh); !createdAt.IsZero() {
+				if createdAt := g.fetchAndSendIssues(ctx, owner, repo, params, isCh, errCh); !createdAt.IsZero() {
 					lastCreatedAt = createdAt
 				}
 			}
@@ -169,8 +202,8 @@ func (g *GitHubIssues) streamIssues(ctx context.Context, owner, repo string, par
 	return isCh, errCh
 }
 
-func (g *GitHubIssues) fetchAndSendIssues(owner, repo string, params url.Values, isCh chan<- githubPkg.Issues, errCh chan<- error) time.Time {
-	is, err := g.fetchIssues(owner, repo, params)
+func (g *GitHubIssues) fetchAndSendIssues(ctx context.Context, owner, repo string, params url.Values, isCh chan<- githubPkg.Issues, errCh chan<- error) time.Time {
+	is, err := g.fetchIssues(ctx, owner, repo, params)
 	if err != nil {
 		errCh <- err
 		return time.Time{}
@@ -183,9 +216,9 @@ func (g *GitHubIssues) fetchAndSendIssues(owner, repo string, params url.Values,
 	return is[0].CreatedAt
 }
 
-func (g *GitHubIssues) FetchPosts(args []string) (domain.Posts, error) {
+func (g *GitHubIssues) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
 	parsed := g.parseArgs(args)
-	is, err := g.fetchIssues(parsed.owner, parsed.repo, nil)
+	is, err := g.fetchIssues(ctx, parsed.owner, parsed.repo, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -193,12 +226,12 @@ func (g *GitHubIssues) FetchPosts(args []string) (domain.Posts, error) {
 	return is.Adapt(), nil
 }
 
-func (g *GitHubIssues) fetchIssues(owner, repo string, params url.Values) (githubPkg.Issues, error) {
+func (g *GitHubIssues) fetchIssues(ctx context.Context, owner, repo string, params url.Values) (githubPkg.Issues, error) {
 	var is githubPkg.Issues
 	dst := &resp{
 		body: &is,
 	}
-	if err := g.do(req{
+	if err := g.do(ctx, req{
 		method: http.MethodGet, url: g.endpoint("repos", owner, repo, "issues"), params: params,
 	}, dst); err != nil {
 		return nil, err
@@ -207,18 +240,404 @@ func (g *GitHubIssues) fetchIssues(owner, repo string, params url.Values) (githu
 	return is, nil
 }
 
-func (g *GitHubIssues) parseArgs(args []string) githubIssuesArgs {
-	var parsed githubIssuesArgs
+func (g *GitHubIssues) SearchPosts(ctx context.Context, args []string, q string) (domain.Posts, error) {
+	parsed := g.parseArgs(args)
+	if parsed.owner != "" && parsed.repo != "" {
+		q = fmt.Sprintf("%s repo:%s/%s", q, parsed.owner, parsed.repo)
+	}
+	is, err := g.searchIssues(ctx, url.Values{
+		"q": []string{q}, "sort": []string{"created"}, "order": []string{"desc"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return is.Adapt(), nil
+}
+
+func (g *GitHubIssues) searchIssues(ctx context.Context, params url.Values) (githubPkg.Issues, error) {
+	var searched githubPkg.SearchedIssues
+	dst := &resp{
+		body: &searched,
+	}
+	if err := g.do(ctx, req{
+		method: http.MethodGet, url: g.endpoint("search", "issues"), params: params,
+	}, dst); err != nil {
+		return nil, err
+	}
+
+	return searched.Items, nil
+}
+
+func (g *GitHubIssues) CreatePost(ctx context.Context, args []string, text string) (*domain.Post, error) {
+	parsed := g.parseArgs(args)
+	if err := parsed.validate(); err != nil {
+		return nil, err
+	}
+
+	title, body := splitTitleAndBody(text)
+	var i githubPkg.Issue
+	if err := g.do(ctx, req{
+		method: http.MethodPost, url: g.endpoint("repos", parsed.owner, parsed.repo, "issues"),
+		body: map[string]string{
+			"title": title, "body": body,
+		},
+	}, &resp{body: &i}); err != nil {
+		return nil, fmt.Errorf("failed to create post: %s", err)
+	}
+
+	return i.Adapt(), nil
+}
+
+func (g *GitHubIssues) Reply(ctx context.Context, args []string, number, text string) (*domain.Post, error) {
+	parsed := g.parseArgs(args)
+	if err := parsed.validate(); err != nil {
+		return nil, err
+	}
+
+	var c githubPkg.Comment
+	if err := g.do(ctx, req{
+		method: http.MethodPost, url: g.endpoint("repos", parsed.owner, parsed.repo, "issues", number, "comments"),
+		body: map[string]string{
+			"body": text,
+		},
+	}, &resp{body: &c}); err != nil {
+		return nil, fmt.Errorf("failed to reply: %s", err)
+	}
+
+	return c.Adapt(parsed.owner, parsed.repo, number), nil
+}
+
+func (g *GitHubIssues) React(ctx context.Context, args []string, number string, r domain.Reaction) error {
+	var content string
+	switch r {
+	case domain.ReactionLike, domain.ReactionUpvote:
+		content = "+1"
+	case domain.ReactionDownvote:
+		content = "-1"
+	case domain.ReactionBoost:
+		content = "rocket"
+	default:
+		return &domain.UnsupportedReactionError{Reaction: r}
+	}
+
+	parsed := g.parseArgs(args)
+	if err := parsed.validate(); err != nil {
+		return err
+	}
+
+	var reacted interface{}
+	if err := g.do(ctx, req{
+		method: http.MethodPost, url: g.endpoint("repos", parsed.owner, parsed.repo, "issues", number, "reactions"),
+		header: http.Header{
+			"Accept": []string{"application/vnd.github.squirrel-girl-preview+json"},
+		},
+		body: map[string]string{
+			"content": content,
+		},
+	}, &resp{body: &reacted}); err != nil {
+		return fmt.Errorf("failed to react: %s", err)
+	}
+
+	return nil
+}
+
+func (g *GitHubIssues) parseArgs(args []string) githubRepoArgs {
+	var parsed githubRepoArgs
+	parsed.parse(args)
+
+	return parsed
+}
+
+func NewGitHubNotifications(token string) *GitHubNotifications {
+	return &GitHubNotifications{
+		github: github{
+			token: token,
+		},
+	}
+}
+
+var githubNotificationsInterval = pollInterval{def: time.Minute, min: time.Minute}
+
+type GitHubNotifications struct {
+	github
+}
+
+func (g *GitHubNotifications) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
+	nsCh, errCh := g.streamNotifications(ctx, githubNotificationsInterval.of(interval), nil)
+	ch := make(chan domain.Posts)
+	go func() {
+		defer close(ch)
+		for ns := range nsCh {
+			ch <- ns.Adapt()
+		}
+	}()
+
+	return ch, errCh
+}
+
+func (g *GitHubNotifications) streamNotifications(ctx context.Context, interval time.Duration, header http.Header) (<-chan githubPkg.Notifications, <-chan error) {
+	nsCh, errCh := make(chan githubPkg.Notifications), make(chan error)
+	go func() {
+		defer func() {
+			close(nsCh)
+			close(errCh)
+		}()
+
+		var lastUpdatedAt time.Time
+		g.pollWithETag(ctx, interval, header, func(header http.Header) string {
+			ns, etag, err := g.fetchNotifications(ctx, header)
+			if err != nil {
+				errCh <- err
+				return ""
+			}
+			newers := ns.UpdatedAfter(lastUpdatedAt)
+			if len(newers) <= 0 {
+				return etag
+			}
+
+			lastUpdatedAt = newers.LastUpdatedAt()
+			nsCh <- newers
+			return etag
+		}, errCh)
+	}()
+
+	return nsCh, errCh
+}
+
+func (g *GitHubNotifications) React(ctx context.Context, args []string, id string, r domain.Reaction) error {
+	if r != domain.ReactionMarkRead {
+		return &domain.UnsupportedReactionError{Reaction: r}
+	}
+
+	if err := g.do(ctx, req{
+		method: http.MethodPatch, url: g.endpoint("notifications", "threads", id),
+		body: struct{}{},
+	}, nil); err != nil {
+		return fmt.Errorf("failed to react: %s", err)
+	}
+
+	return nil
+}
+
+func (g *GitHubNotifications) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	ns, _, err := g.fetchNotifications(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.Adapt(), nil
+}
+
+func (g *GitHubNotifications) fetchNotifications(ctx context.Context, header http.Header) (githubPkg.Notifications, string, error) {
+	if g.token == "" {
+		return nil, "", errors.New("token of github is required to fetch notifications")
+	}
+
+	var ns githubPkg.Notifications
+	dst := &resp{
+		body: &ns,
+	}
+	if err := g.do(ctx, req{
+		method: http.MethodGet, url: g.endpoint("notifications"), header: header,
+	}, dst); err != nil {
+		return nil, "", err
+	}
+
+	return ns, dst.header.Get("ETag"), nil
+}
+
+func NewGitHubPulls(token string) *GitHubPulls {
+	return &GitHubPulls{
+		github: github{
+			token: token,
+		},
+	}
+}
+
+var githubPullsInterval = pollInterval{def: 5 * time.Minute, min: time.Minute}
+
+type GitHubPulls struct {
+	github
+}
+
+func (g *GitHubPulls) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
+	parsed := g.parseArgs(args)
+	psCh, errCh := g.streamPulls(ctx, githubPullsInterval.of(interval), parsed.owner, parsed.repo, nil)
+	ch := make(chan domain.Posts)
+	go func() {
+		defer close(ch)
+		for ps := range psCh {
+			ch <- ps.Adapt()
+		}
+	}()
+
+	return ch, errCh
+}
+
+func (g *GitHubPulls) streamPulls(ctx context.Context, interval time.Duration, owner, repo string, header http.Header) (<-chan githubPkg.Pulls, <-chan error) {
+	psCh, errCh := make(chan githubPkg.Pulls), make(chan error)
+	go func() {
+		defer func() {
+			close(psCh)
+			close(errCh)
+		}()
+
+		var lastCreatedAt time.Time
+		g.pollWithETag(ctx, interval, header, func(header http.Header) string {
+			ps, etag, err := g.fetchPulls(ctx, owner, repo, header)
+			if err != nil {
+				errCh <- err
+				return ""
+			}
+			var newers githubPkg.Pulls
+			for _, p := range ps {
+				if p.CreatedAt.After(lastCreatedAt) {
+					newers = append(newers, p)
+				}
+			}
+			if len(newers) <= 0 {
+				return etag
+			}
+
+			lastCreatedAt = newers[0].CreatedAt
+			psCh <- newers
+			return etag
+		}, errCh)
+	}()
+
+	return psCh, errCh
+}
+
+func (g *GitHubPulls) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	parsed := g.parseArgs(args)
+	ps, _, err := g.fetchPulls(ctx, parsed.owner, parsed.repo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ps.Adapt(), nil
+}
+
+func (g *GitHubPulls) fetchPulls(ctx context.Context, owner, repo string, header http.Header) (githubPkg.Pulls, string, error) {
+	var ps githubPkg.Pulls
+	dst := &resp{
+		body: &ps,
+	}
+	if err := g.do(ctx, req{
+		method: http.MethodGet, url: g.endpoint("repos", owner, repo, "pulls"), header: header,
+	}, dst); err != nil {
+		return nil, "", err
+	}
+
+	return ps, dst.header.Get("ETag"), nil
+}
+
+func (g *GitHubPulls) parseArgs(args []string) githubRepoArgs {
+	var parsed githubRepoArgs
 	parsed.parse(args)
 
 	return parsed
 }
 
-type githubIssuesArgs struct {
+func NewGitHubReleases(token string) *GitHubReleases {
+	return &GitHubReleases{
+		github: github{
+			token: token,
+		},
+	}
+}
+
+var githubReleasesInterval = pollInterval{def: 5 * time.Minute, min: time.Minute}
+
+type GitHubReleases struct {
+	github
+}
+
+func (g *GitHubReleases) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
+	parsed := g.parseArgs(args)
+	rsCh, errCh := g.streamReleases(ctx, githubReleasesInterval.of(interval), parsed.owner, parsed.repo, nil)
+	ch := make(chan domain.Posts)
+	go func() {
+		defer close(ch)
+		for rs := range rsCh {
+			ch <- rs.Adapt()
+		}
+	}()
+
+	return ch, errCh
+}
+
+func (g *GitHubReleases) streamReleases(ctx context.Context, interval time.Duration, owner, repo string, header http.Header) (<-chan githubPkg.Releases, <-chan error) {
+	rsCh, errCh := make(chan githubPkg.Releases), make(chan error)
+	go func() {
+		defer func() {
+			close(rsCh)
+			close(errCh)
+		}()
+
+		var lastCreatedAt time.Time
+		g.pollWithETag(ctx, interval, header, func(header http.Header) string {
+			rs, etag, err := g.fetchReleases(ctx, owner, repo, header)
+			if err != nil {
+				errCh <- err
+				return ""
+			}
+			var newers githubPkg.Releases
+			for _, r := range rs {
+				if r.CreatedAt.After(lastCreatedAt) {
+					newers = append(newers, r)
+				}
+			}
+			if len(newers) <= 0 {
+				return etag
+			}
+
+			lastCreatedAt = newers[0].CreatedAt
+			rsCh <- newers
+			return etag
+		}, errCh)
+	}()
+
+	return rsCh, errCh
+}
+
+func (g *GitHubReleases) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	parsed := g.parseArgs(args)
+	rs, _, err := g.fetchReleases(ctx, parsed.owner, parsed.repo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.Adapt(), nil
+}
+
+func (g *GitHubReleases) fetchReleases(ctx context.Context, owner, repo string, header http.Header) (githubPkg.Releases, string, error) {
+	var rs githubPkg.Releases
+	dst := &resp{
+		body: &rs,
+	}
+	if err := g.do(ctx, req{
+		method: http.MethodGet, url: g.endpoint("repos", owner, repo, "releases"), header: header,
+	}, dst); err != nil {
+		return nil, "", err
+	}
+
+	return rs, dst.header.Get("ETag"), nil
+}
+
+func (g *GitHubReleases) parseArgs(args []string) githubRepoArgs {
+	var parsed githubRepoArgs
+	parsed.parse(args)
+
+	return parsed
+}
+
+type githubRepoArgs struct {
 	owner, repo string
 }
 
-func (as *githubIssuesArgs) parse(args []string) {
+func (as *githubRepoArgs) parse(args []string) {
 	if len(args) <= 0 {
 		return
 	}
@@ -230,19 +649,83 @@ func (as *githubIssuesArgs) parse(args []string) {
 	}
 }
 
-type github struct{}
+func (as githubRepoArgs) validate() error {
+	if as.owner == "" || as.repo == "" {
+		return errors.New("owner and repo should be specified as owner/repo")
+	}
+
+	return nil
+}
+
+type github struct {
+	token string
+}
+
+func (g *github) rateLimit() *githubRateLimit {
+	return githubRateLimitOf(g.token)
+}
+
+func (g *github) RateLimit() GitHubRateLimit {
+	return g.rateLimit().get()
+}
+
+func (g *github) waitFor(interval time.Duration) time.Duration {
+	limit := g.rateLimit().get()
+	if !limit.isExceeded() {
+		return interval
+	}
+	if untilReset := time.Until(limit.Reset); interval < untilReset {
+		return untilReset
+	}
+
+	return interval
+}
+
+func (g *github) pollWithETag(ctx context.Context, interval time.Duration, header http.Header, fetchAndSend func(http.Header) string, errCh chan<- error) {
+	lastETag := fetchAndSend(header)
+	for {
+		select {
+		case <-ctx.Done():
+			errCh <- ctx.Err()
+			return
+		case <-time.After(g.waitFor(interval)):
+			if lastETag != "" {
+				if header == nil {
+					header = make(http.Header)
+				}
+				header.Set("If-None-Match", lastETag)
+			}
+			if etag := fetchAndSend(header); etag != "" {
+				lastETag = etag
+			}
+		}
+	}
+}
+
+func (g *github) do(ctx context.Context, r req, dst *resp) error {
+	if g.token != "" {
+		authorized := make(http.Header)
+		for k, vs := range r.header {
+			authorized[k] = vs
+		}
+		authorized.Set("Authorization", fmt.Sprintf("token %s", g.token))
+		r.header = authorized
+	}
 
-func (g *github) do(r req, dst *resp) error {
-	resp, err := r.do()
+	resp, err := r.do(ctx)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	limit := g.rateLimit().update(resp.Header)
+	if resp.StatusCode == http.StatusForbidden && limit.isExceeded() {
+		return fmt.Errorf("rate limit of github is exceeded until %s", limit.Reset.Format("2006/01/02 15:04:05"))
+	}
 	if http.StatusBadRequest <= resp.StatusCode {
 		return errors.New(resp.Status)
 	}
-	if resp.StatusCode == http.StatusNotModified {
+	if resp.StatusCode == http.StatusNotModified || dst == nil {
 		return nil
 	}
 
@@ -256,3 +739,66 @@ func (g *github) endpoint(ps ...string) string {
 	parsed.Path = filepath.Join(ss...)
 	return parsed.String()
 }
+
+var (
+	githubRateLimitsMu sync.Mutex
+	githubRateLimits   = make(map[string]*githubRateLimit)
+)
+
+func githubRateLimitOf(token string) *githubRateLimit {
+	githubRateLimitsMu.Lock()
+	defer githubRateLimitsMu.Unlock()
+
+	l, ok := githubRateLimits[token]
+	if !ok {
+		l = new(githubRateLimit)
+		githubRateLimits[token] = l
+	}
+
+	return l
+}
+
+type githubRateLimit struct {
+	mu    sync.Mutex
+	limit GitHubRateLimit
+}
+
+func (l *githubRateLimit) get() GitHubRateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.limit
+}
+
+func (l *githubRateLimit) update(header http.Header) GitHubRateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return l.limit
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return l.limit
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return l.limit
+	}
+
+	l.limit = GitHubRateLimit{
+		Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0),
+	}
+
+	return l.limit
+}
+
+type GitHubRateLimit struct {
+	Limit, Remaining int
+	Reset            time.Time
+}
+
+func (l GitHubRateLimit) isExceeded() bool {
+	return l.Limit > 0 && l.Remaining <= 0 && time.Now().Before(l.Reset)
+}
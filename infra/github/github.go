@@ -2,6 +2,7 @@ package github
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/tomocy/smoothie/domain"
@@ -19,34 +20,162 @@ func (es Events) Adapt() domain.Posts {
 }
 
 type Event struct {
-	ID    string `json:"id"`
-	Type  string `json:"type"`
-	Actor *User  `json:"actor"`
-	Repo  struct {
-		Name string `json:"name"`
-	} `json:"repo"`
-	Payload struct {
-		Action string `json:"action"`
-	} `jsno:"payload"`
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Actor     *User     `json:"actor"`
+	Repo      eventRepo `json:"repo"`
+	Payload   payload   `json:"payload"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type eventRepo struct {
+	Name string `json:"name"`
+}
+
+type payload struct {
+	Action  string `json:"action"`
+	Ref     string `json:"ref"`
+	RefType string `json:"ref_type"`
+	Commits []*struct {
+		SHA     string `json:"sha"`
+		Message string `json:"message"`
+	} `json:"commits"`
+	Number      int `json:"number"`
+	PullRequest *struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	} `json:"pull_request"`
+	Issue *struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	} `json:"issue"`
+	Comment *struct {
+		Body string `json:"body"`
+	} `json:"comment"`
+	Review *struct {
+		State string `json:"state"`
+		Body  string `json:"body"`
+	} `json:"review"`
+	Release *struct {
+		TagName string `json:"tag_name"`
+		Name    string `json:"name"`
+	} `json:"release"`
+	Forkee *struct {
+		FullName string `json:"full_name"`
+	} `json:"forkee"`
+	Member *User `json:"member"`
+	Pages  []*struct {
+		PageName string `json:"page_name"`
+		Action   string `json:"action"`
+	} `json:"pages"`
+}
+
 func (e *Event) Adapt() *domain.Post {
 	return &domain.Post{
 		ID:        fmt.Sprint(e.ID),
 		Driver:    "github event",
 		User:      e.Actor.Adapt(),
+		Channel:   e.Repo.Name,
 		Text:      e.joinText(),
+		URL:       fmt.Sprintf("https://github.com/%s", e.Repo.Name),
 		CreatedAt: e.CreatedAt,
 	}
 }
 
 func (e *Event) joinText() string {
-	if e.Type != "WatchEvent" {
-		return ""
+	p := e.Payload
+	var b strings.Builder
+	switch e.Type {
+	case "WatchEvent":
+		fmt.Fprintf(&b, "%s %s", p.Action, e.Repo.Name)
+	case "PushEvent":
+		fmt.Fprintf(&b, "pushed to %s at %s", strings.TrimPrefix(p.Ref, "refs/heads/"), e.Repo.Name)
+		for _, c := range p.Commits {
+			fmt.Fprintf(&b, "\n%s %s", shortenSHA(c.SHA), firstLine(c.Message))
+		}
+	case "CreateEvent":
+		if p.RefType == "repository" {
+			fmt.Fprintf(&b, "created repository %s", e.Repo.Name)
+			break
+		}
+		fmt.Fprintf(&b, "created %s %s at %s", p.RefType, p.Ref, e.Repo.Name)
+	case "DeleteEvent":
+		fmt.Fprintf(&b, "deleted %s %s at %s", p.RefType, p.Ref, e.Repo.Name)
+	case "ForkEvent":
+		if p.Forkee != nil {
+			fmt.Fprintf(&b, "forked %s to %s", e.Repo.Name, p.Forkee.FullName)
+			break
+		}
+		fmt.Fprintf(&b, "forked %s", e.Repo.Name)
+	case "IssuesEvent":
+		if p.Issue != nil {
+			fmt.Fprintf(&b, "%s issue %s#%d\n%s", p.Action, e.Repo.Name, p.Issue.Number, p.Issue.Title)
+		}
+	case "IssueCommentEvent":
+		if p.Issue != nil {
+			fmt.Fprintf(&b, "commented on %s#%d\n%s", e.Repo.Name, p.Issue.Number, p.Issue.Title)
+		}
+		if p.Comment != nil {
+			fmt.Fprintf(&b, "\n%s", p.Comment.Body)
+		}
+	case "PullRequestEvent":
+		if p.PullRequest != nil {
+			fmt.Fprintf(&b, "%s pull request %s#%d\n%s", p.Action, e.Repo.Name, p.PullRequest.Number, p.PullRequest.Title)
+		}
+	case "PullRequestReviewEvent":
+		if p.PullRequest != nil {
+			fmt.Fprintf(&b, "reviewed pull request %s#%d\n%s", e.Repo.Name, p.PullRequest.Number, p.PullRequest.Title)
+		}
+		if p.Review != nil && p.Review.Body != "" {
+			fmt.Fprintf(&b, "\n%s", p.Review.Body)
+		}
+	case "PullRequestReviewCommentEvent":
+		if p.PullRequest != nil {
+			fmt.Fprintf(&b, "commented on pull request %s#%d\n%s", e.Repo.Name, p.PullRequest.Number, p.PullRequest.Title)
+		}
+		if p.Comment != nil {
+			fmt.Fprintf(&b, "\n%s", p.Comment.Body)
+		}
+	case "ReleaseEvent":
+		if p.Release != nil {
+			fmt.Fprintf(&b, "%s release %s at %s", p.Action, p.Release.TagName, e.Repo.Name)
+			if p.Release.Name != "" && p.Release.Name != p.Release.TagName {
+				fmt.Fprintf(&b, "\n%s", p.Release.Name)
+			}
+		}
+	case "MemberEvent":
+		if p.Member != nil {
+			fmt.Fprintf(&b, "%s @%s to %s", p.Action, p.Member.Login, e.Repo.Name)
+		}
+	case "PublicEvent":
+		fmt.Fprintf(&b, "made %s public", e.Repo.Name)
+	case "GollumEvent":
+		fmt.Fprintf(&b, "updated wiki of %s", e.Repo.Name)
+		for _, page := range p.Pages {
+			fmt.Fprintf(&b, "\n%s %s", page.Action, page.PageName)
+		}
+	}
+	if b.Len() <= 0 {
+		fmt.Fprintf(&b, "%s %s", strings.TrimSuffix(e.Type, "Event"), e.Repo.Name)
+	}
+
+	return b.String()
+}
+
+func shortenSHA(sha string) string {
+	if len(sha) <= 7 {
+		return sha
 	}
 
-	return fmt.Sprintf("%s %s", e.Payload.Action, e.Repo.Name)
+	return sha[:7]
+}
+
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
+
+type SearchedIssues struct {
+	Items Issues `json:"items"`
 }
 
 type Issues []*Issue
@@ -61,23 +190,242 @@ func (is Issues) Adapt() domain.Posts {
 }
 
 type Issue struct {
+	ID            int       `json:"id"`
+	Number        int       `json:"number"`
+	User          *User     `json:"user"`
+	Title         string    `json:"title"`
+	Body          string    `json:"body"`
+	HTMLURL       string    `json:"html_url"`
+	RepositoryURL string    `json:"repository_url"`
+	Labels        labels    `json:"labels"`
+	Comments      int       `json:"comments"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (i *Issue) Adapt() *domain.Post {
+	return &domain.Post{
+		ID:      fmt.Sprint(i.Number),
+		Driver:  "github",
+		User:    i.User.Adapt(),
+		Channel: strings.TrimPrefix(i.RepositoryURL, "https://api.github.com/repos/"),
+		Title:   fmt.Sprintf("#%d %s", i.Number, i.Title),
+		Text:    i.Body,
+		URL:     i.HTMLURL,
+		Tags:    i.Labels.names(),
+		Metrics: domain.Metrics{
+			Replies: i.Comments,
+		},
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+type Comment struct {
 	ID        int       `json:"id"`
 	User      *User     `json:"user"`
-	Title     string    `json:"title"`
 	Body      string    `json:"body"`
+	HTMLURL   string    `json:"html_url"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
-func (i *Issue) Adapt() *domain.Post {
+func (c *Comment) Adapt(owner, repo string, number string) *domain.Post {
 	return &domain.Post{
-		ID:        fmt.Sprint(i.ID),
+		ID:        fmt.Sprint(c.ID),
 		Driver:    "github",
-		User:      i.User.Adapt(),
-		Text:      fmt.Sprintf("%s\n%s", i.Title, i.Body),
-		CreatedAt: i.CreatedAt,
+		User:      c.User.Adapt(),
+		Channel:   fmt.Sprintf("%s/%s", owner, repo),
+		Text:      c.Body,
+		URL:       c.HTMLURL,
+		ParentID:  number,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+type labels []*struct {
+	Name string `json:"name"`
+}
+
+func (ls labels) names() []string {
+	names := make([]string, len(ls))
+	for i, l := range ls {
+		names[i] = l.Name
+	}
+
+	return names
+}
+
+type Notifications []*Notification
+
+func (ns Notifications) Adapt() domain.Posts {
+	adapteds := make(domain.Posts, len(ns))
+	for i, n := range ns {
+		adapteds[i] = n.Adapt()
+	}
+
+	return adapteds
+}
+
+func (ns Notifications) UpdatedAfter(t time.Time) Notifications {
+	var afters Notifications
+	for _, n := range ns {
+		if n.UpdatedAt.After(t) {
+			afters = append(afters, n)
+		}
+	}
+
+	return afters
+}
+
+func (ns Notifications) LastUpdatedAt() time.Time {
+	var last time.Time
+	for _, n := range ns {
+		if n.UpdatedAt.After(last) {
+			last = n.UpdatedAt
+		}
+	}
+
+	return last
+}
+
+type Notification struct {
+	ID      string `json:"id"`
+	Reason  string `json:"reason"`
+	Subject struct {
+		Title string `json:"title"`
+		Type  string `json:"type"`
+		URL   string `json:"url"`
+	} `json:"subject"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		Owner    *User  `json:"owner"`
+	} `json:"repository"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (n *Notification) Adapt() *domain.Post {
+	return &domain.Post{
+		ID:        n.ID,
+		Driver:    "github",
+		User:      n.Repository.Owner.Adapt(),
+		Channel:   n.Repository.FullName,
+		Title:     n.Subject.Title,
+		Text:      fmt.Sprintf("[%s] %s", n.Reason, n.Subject.Type),
+		URL:       n.htmlURL(),
+		CreatedAt: n.UpdatedAt,
+	}
+}
+
+func (n *Notification) htmlURL() string {
+	if n.Subject.URL == "" {
+		return fmt.Sprintf("https://github.com/%s", n.Repository.FullName)
+	}
+
+	replaced := strings.Replace(n.Subject.URL, "https://api.github.com/repos/", "https://github.com/", 1)
+	return strings.Replace(replaced, "/pulls/", "/pull/", 1)
+}
+
+type Pulls []*Pull
+
+func (ps Pulls) Adapt() domain.Posts {
+	adapteds := make(domain.Posts, len(ps))
+	for i, p := range ps {
+		adapteds[i] = p.Adapt()
+	}
+
+	return adapteds
+}
+
+type Pull struct {
+	ID                 int     `json:"id"`
+	Number             int     `json:"number"`
+	User               *User   `json:"user"`
+	Title              string  `json:"title"`
+	Body               string  `json:"body"`
+	HTMLURL            string  `json:"html_url"`
+	Labels             labels  `json:"labels"`
+	RequestedReviewers []*User `json:"requested_reviewers"`
+	Base               struct {
+		Repo struct {
+			FullName string `json:"full_name"`
+		} `json:"repo"`
+	} `json:"base"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (p *Pull) Adapt() *domain.Post {
+	return &domain.Post{
+		ID:        fmt.Sprint(p.ID),
+		Driver:    "github",
+		User:      p.User.Adapt(),
+		Channel:   p.Base.Repo.FullName,
+		Title:     fmt.Sprintf("#%d %s", p.Number, p.Title),
+		Text:      p.joinText(),
+		URL:       p.HTMLURL,
+		Tags:      p.Labels.names(),
+		CreatedAt: p.CreatedAt,
 	}
 }
 
+func (p *Pull) joinText() string {
+	var b strings.Builder
+	if len(p.RequestedReviewers) > 0 {
+		reviewers := make([]string, len(p.RequestedReviewers))
+		for i, r := range p.RequestedReviewers {
+			reviewers[i] = "@" + r.Login
+		}
+		fmt.Fprintf(&b, "review requested: %s\n", strings.Join(reviewers, " "))
+	}
+	b.WriteString(p.Body)
+
+	return b.String()
+}
+
+type Releases []*Release
+
+func (rs Releases) Adapt() domain.Posts {
+	adapteds := make(domain.Posts, len(rs))
+	for i, r := range rs {
+		adapteds[i] = r.Adapt()
+	}
+
+	return adapteds
+}
+
+type Release struct {
+	ID         int       `json:"id"`
+	TagName    string    `json:"tag_name"`
+	Name       string    `json:"name"`
+	Body       string    `json:"body"`
+	Prerelease bool      `json:"prerelease"`
+	HTMLURL    string    `json:"html_url"`
+	Author     *User     `json:"author"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (r *Release) Adapt() *domain.Post {
+	return &domain.Post{
+		ID:        fmt.Sprint(r.ID),
+		Driver:    "github",
+		User:      r.Author.Adapt(),
+		Title:     r.joinTitle(),
+		Text:      r.Body,
+		URL:       r.HTMLURL,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r *Release) joinTitle() string {
+	var b strings.Builder
+	b.WriteString(r.TagName)
+	if r.Name != "" && r.Name != r.TagName {
+		fmt.Fprintf(&b, " %s", r.Name)
+	}
+	if r.Prerelease {
+		b.WriteString(" (pre-release)")
+	}
+
+	return b.String()
+}
+
 type User struct {
 	Login string `json:"login"`
 }
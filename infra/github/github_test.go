@@ -0,0 +1,123 @@
+package github
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEventJoinText(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected string
+	}{
+		"watch": {
+			src:      `{"type": "WatchEvent", "repo": {"name": "golang/go"}, "payload": {"action": "started"}}`,
+			expected: "started golang/go",
+		},
+		"push": {
+			src: `{"type": "PushEvent", "repo": {"name": "golang/go"}, "payload": {"ref": "refs/heads/master", "commits": [
+				{"sha": "0123456789abcdef", "message": "fix bug\n\ndetails"},
+				{"sha": "fedcba9", "message": "add test"}
+			]}}`,
+			expected: "pushed to master at golang/go\n0123456 fix bug\nfedcba9 add test",
+		},
+		"create repository": {
+			src:      `{"type": "CreateEvent", "repo": {"name": "tomocy/smoothie"}, "payload": {"ref_type": "repository"}}`,
+			expected: "created repository tomocy/smoothie",
+		},
+		"create branch": {
+			src:      `{"type": "CreateEvent", "repo": {"name": "tomocy/smoothie"}, "payload": {"ref": "feature", "ref_type": "branch"}}`,
+			expected: "created branch feature at tomocy/smoothie",
+		},
+		"delete": {
+			src:      `{"type": "DeleteEvent", "repo": {"name": "tomocy/smoothie"}, "payload": {"ref": "v1", "ref_type": "tag"}}`,
+			expected: "deleted tag v1 at tomocy/smoothie",
+		},
+		"fork": {
+			src:      `{"type": "ForkEvent", "repo": {"name": "golang/go"}, "payload": {"forkee": {"full_name": "tomocy/go"}}}`,
+			expected: "forked golang/go to tomocy/go",
+		},
+		"fork without forkee": {
+			src:      `{"type": "ForkEvent", "repo": {"name": "golang/go"}, "payload": {}}`,
+			expected: "forked golang/go",
+		},
+		"issues": {
+			src:      `{"type": "IssuesEvent", "repo": {"name": "golang/go"}, "payload": {"action": "opened", "issue": {"number": 1, "title": "bug"}}}`,
+			expected: "opened issue golang/go#1\nbug",
+		},
+		"issue comment": {
+			src:      `{"type": "IssueCommentEvent", "repo": {"name": "golang/go"}, "payload": {"issue": {"number": 1, "title": "bug"}, "comment": {"body": "LGTM"}}}`,
+			expected: "commented on golang/go#1\nbug\nLGTM",
+		},
+		"pull request": {
+			src:      `{"type": "PullRequestEvent", "repo": {"name": "golang/go"}, "payload": {"action": "closed", "pull_request": {"number": 2, "title": "feature"}}}`,
+			expected: "closed pull request golang/go#2\nfeature",
+		},
+		"pull request review": {
+			src:      `{"type": "PullRequestReviewEvent", "repo": {"name": "golang/go"}, "payload": {"pull_request": {"number": 2, "title": "feature"}, "review": {"state": "approved", "body": "nice"}}}`,
+			expected: "reviewed pull request golang/go#2\nfeature\nnice",
+		},
+		"pull request review comment": {
+			src:      `{"type": "PullRequestReviewCommentEvent", "repo": {"name": "golang/go"}, "payload": {"pull_request": {"number": 2, "title": "feature"}, "comment": {"body": "typo"}}}`,
+			expected: "commented on pull request golang/go#2\nfeature\ntypo",
+		},
+		"release": {
+			src:      `{"type": "ReleaseEvent", "repo": {"name": "golang/go"}, "payload": {"action": "published", "release": {"tag_name": "go1.13", "name": "Go 1.13"}}}`,
+			expected: "published release go1.13 at golang/go\nGo 1.13",
+		},
+		"release named as tag": {
+			src:      `{"type": "ReleaseEvent", "repo": {"name": "golang/go"}, "payload": {"action": "published", "release": {"tag_name": "go1.13", "name": "go1.13"}}}`,
+			expected: "published release go1.13 at golang/go",
+		},
+		"member": {
+			src:      `{"type": "MemberEvent", "repo": {"name": "tomocy/smoothie"}, "payload": {"action": "added", "member": {"login": "gopher"}}}`,
+			expected: "added @gopher to tomocy/smoothie",
+		},
+		"public": {
+			src:      `{"type": "PublicEvent", "repo": {"name": "tomocy/smoothie"}, "payload": {}}`,
+			expected: "made tomocy/smoothie public",
+		},
+		"gollum": {
+			src:      `{"type": "GollumEvent", "repo": {"name": "tomocy/smoothie"}, "payload": {"pages": [{"page_name": "Home", "action": "edited"}]}}`,
+			expected: "updated wiki of tomocy/smoothie\nedited Home",
+		},
+		"unknown": {
+			src:      `{"type": "SponsorshipEvent", "repo": {"name": "tomocy/smoothie"}, "payload": {}}`,
+			expected: "Sponsorship tomocy/smoothie",
+		},
+		"issues without issue": {
+			src:      `{"type": "IssuesEvent", "repo": {"name": "golang/go"}, "payload": {"action": "opened"}}`,
+			expected: "Issues golang/go",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var e Event
+			if err := json.Unmarshal([]byte(test.src), &e); err != nil {
+				t.Fatalf("unexpected error by json.Unmarshal: got %s, expect <nil>\n", err)
+			}
+			if actual := e.joinText(); actual != test.expected {
+				t.Errorf("unexpected text by (*Event).joinText: got %q, expect %q\n", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestIssueAdapt(t *testing.T) {
+	var i Issue
+	if err := json.Unmarshal([]byte(`{
+		"id": 501247936, "number": 34567, "user": {"login": "tomocy"}, "title": "cmd/go: fix bug",
+		"html_url": "https://github.com/golang/go/issues/34567", "repository_url": "https://api.github.com/repos/golang/go"
+	}`), &i); err != nil {
+		t.Fatalf("unexpected error by json.Unmarshal: got %s, expect <nil>\n", err)
+	}
+
+	adapted := i.Adapt()
+	if adapted.ID != "34567" {
+		t.Errorf("unexpected id by (*Issue).Adapt: got %s, expect 34567\n", adapted.ID)
+	}
+	if adapted.Channel != "golang/go" {
+		t.Errorf("unexpected channel by (*Issue).Adapt: got %s, expect golang/go\n", adapted.Channel)
+	}
+}
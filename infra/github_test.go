@@ -0,0 +1,93 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitHubDoWithRateLimit(t *testing.T) {
+	token := "smoothie"
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != fmt.Sprintf("token %s", token) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	g := NewGitHubEvents(token)
+	var body interface{}
+	if err := g.do(context.Background(), req{method: http.MethodGet, url: srv.URL}, &resp{body: &body}); err == nil {
+		t.Errorf("unexpected error by (*github).do: got <nil>, expect error of rate limit\n")
+	}
+
+	limit := g.RateLimit()
+	if limit.Limit != 5000 {
+		t.Errorf("unexpected limit of rate limit: got %d, expect 5000\n", limit.Limit)
+	}
+	if limit.Remaining != 0 {
+		t.Errorf("unexpected remaining of rate limit: got %d, expect 0\n", limit.Remaining)
+	}
+	if !limit.Reset.Equal(reset) {
+		t.Errorf("unexpected reset of rate limit: got %s, expect %s\n", limit.Reset, reset)
+	}
+	if waited := g.waitFor(time.Minute); waited <= time.Minute {
+		t.Errorf("unexpected duration to wait for rate limit: got %s, expect more than %s\n", waited, time.Minute)
+	}
+}
+
+func TestGitHubRateLimitSharedByToken(t *testing.T) {
+	token := "shared"
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	events := NewGitHubEvents(token)
+	var body interface{}
+	events.do(context.Background(), req{method: http.MethodGet, url: srv.URL}, &resp{body: &body})
+
+	issues := NewGitHubIssues(token)
+	if waited := issues.waitFor(time.Minute); waited <= time.Minute {
+		t.Errorf("unexpected duration to wait for rate limit of the same token: got %s, expect more than %s\n", waited, time.Minute)
+	}
+	others := NewGitHubIssues("other")
+	if waited := others.waitFor(time.Minute); waited != time.Minute {
+		t.Errorf("unexpected duration to wait for rate limit of another token: got %s, expect %s\n", waited, time.Minute)
+	}
+}
+
+func TestGitHubEventsArgsPath(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"user": {args: []string{"tomocy"}, expected: "users/tomocy/received_events"},
+		"org":  {args: []string{"org", "golang"}, expected: "orgs/golang/events"},
+		"repo": {args: []string{"repo", "golang/go"}, expected: "repos/golang/go/events"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parsed := new(GitHubEvents).parseArgs(test.args)
+			if actual := strings.Join(parsed.path(), "/"); actual != test.expected {
+				t.Errorf("unexpected path by (*githubEventsArgs).path: got %s, expect %s\n", actual, test.expected)
+			}
+		})
+	}
+}
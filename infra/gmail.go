@@ -25,7 +25,7 @@ func NewGmail(id, secret string, presenter authURLPresenter) *Gmail {
 				RedirectURL: "http://localhost/smoothie/gmail/authorization",
 				Endpoint:    google.Endpoint,
 				Scopes: []string{
-					"https://www.googleapis.com/auth/gmail.readonly",
+					"https://www.googleapis.com/auth/gmail.modify",
 				},
 			},
 		},
@@ -33,13 +33,15 @@ func NewGmail(id, secret string, presenter authURLPresenter) *Gmail {
 	}
 }
 
+var gmailInterval = pollInterval{def: 5 * time.Minute, min: time.Minute}
+
 type Gmail struct {
 	oauth     oauth2Manager
 	presenter authURLPresenter
 }
 
-func (g *Gmail) StreamPosts(ctx context.Context, args []string) (<-chan domain.Posts, <-chan error) {
-	msCh, errCh := g.streamMessages(ctx, nil)
+func (g *Gmail) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
+	msCh, errCh := g.streamMessages(ctx, gmailInterval.of(interval), nil)
 	ch := make(chan domain.Posts)
 	go func() {
 		defer close(ch)
@@ -51,7 +53,7 @@ func (g *Gmail) StreamPosts(ctx context.Context, args []string) (<-chan domain.P
 	return ch, errCh
 }
 
-func (g *Gmail) streamMessages(ctx context.Context, params url.Values) (<-chan gmail.Messages, <-chan error) {
+func (g *Gmail) streamMessages(ctx context.Context, interval time.Duration, params url.Values) (<-chan gmail.Messages, <-chan error) {
 	msCh, errCh := make(chan gmail.Messages), make(chan error)
 	go func() {
 		defer func() {
@@ -59,20 +61,20 @@ func (g *Gmail) streamMessages(ctx context.Context, params url.Values) (<-chan g
 			close(errCh)
 		}()
 
-		lastCreatedAt := g.fetchAndSendMessages(params, msCh, errCh)
+		lastCreatedAt := g.fetchAndSendMessages(ctx, params, msCh, errCh)
 		for {
 			select {
 			case <-ctx.Done():
 				errCh <- ctx.Err()
 				return
-			case <-time.After(5 * time.Minute):
+			case <-time.After(interval):
 				if !lastCreatedAt.IsZero() {
 					if params == nil {
 						params = make(url.Values)
 					}
 					params.Set("q", fmt.Sprintf("newer:%s", lastCreatedAt.Format("2006/01/02 15:04")))
 				}
-				if createdAt := g.fetchAndSendMessages(params, msCh, errCh); !createdAt.IsZero() {
+				if createdAt := g.fetchAndSendMessages(ctx, params, msCh, errCh); !createdAt.IsZero() {
 					lastCreatedAt = createdAt
 				}
 			}
@@ -82,8 +84,8 @@ func (g *Gmail) streamMessages(ctx context.Context, params url.Values) (<-chan g
 	return msCh, errCh
 }
 
-func (g *Gmail) fetchAndSendMessages(params url.Values, msCh chan<- gmail.Messages, errCh chan<- error) time.Time {
-	ms, err := g.fetchMessages(params)
+func (g *Gmail) fetchAndSendMessages(ctx context.Context, params url.Values, msCh chan<- gmail.Messages, errCh chan<- error) time.Time {
+	ms, err := g.fetchMessages(ctx, params)
 	if err != nil {
 		errCh <- err
 		return time.Time{}
@@ -98,8 +100,19 @@ func (g *Gmail) fetchAndSendMessages(params url.Values, msCh chan<- gmail.Messag
 	return lastCreatedAt
 }
 
-func (g *Gmail) FetchPosts(args []string) (domain.Posts, error) {
-	ms, err := g.fetchMessages(nil)
+func (g *Gmail) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	ms, err := g.fetchMessages(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ms.Adapt(), nil
+}
+
+func (g *Gmail) SearchPosts(ctx context.Context, args []string, q string) (domain.Posts, error) {
+	ms, err := g.fetchMessages(ctx, url.Values{
+		"q": []string{q},
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -107,13 +120,47 @@ func (g *Gmail) FetchPosts(args []string) (domain.Posts, error) {
 	return ms.Adapt(), nil
 }
 
-func (g *Gmail) fetchMessages(params url.Values) (gmail.Messages, error) {
+func (g *Gmail) React(ctx context.Context, args []string, id string, r domain.Reaction) error {
+	var add, remove []string
+	switch r {
+	case domain.ReactionLike, domain.ReactionStar:
+		add = []string{"STARRED"}
+	case domain.ReactionArchive:
+		remove = []string{"INBOX"}
+	case domain.ReactionMarkRead:
+		remove = []string{"UNREAD"}
+	default:
+		return &domain.UnsupportedReactionError{Reaction: r}
+	}
+
+	tok, err := g.retreiveAuthorization()
+	if err != nil {
+		return err
+	}
+
+	var m *gmailLib.Message
+	if err := g.do(ctx, oauth2Req{
+		tok: tok,
+		req: req{
+			method: http.MethodPost, url: g.endpoint("/users/me/messages", id, "modify"),
+			body: &gmailLib.ModifyMessageRequest{
+				AddLabelIds: add, RemoveLabelIds: remove,
+			},
+		},
+	}, &m); err != nil {
+		return fmt.Errorf("failed to react: %s", err)
+	}
+
+	return g.saveAccessToken(tok)
+}
+
+func (g *Gmail) fetchMessages(ctx context.Context, params url.Values) (gmail.Messages, error) {
 	tok, err := g.retreiveAuthorization()
 	if err != nil {
 		return nil, err
 	}
 
-	ms, err := g.listAndGetMessages(tok, params)
+	ms, err := g.listAndGetMessages(ctx, tok, params)
 	if err != nil {
 		g.resetAccessToken()
 		return nil, err
@@ -131,8 +178,17 @@ func (g *Gmail) fetchMessages(params url.Values) (gmail.Messages, error) {
 	return casteds, nil
 }
 
+func (g *Gmail) Authorize(ctx context.Context, args []string) error {
+	tok, err := g.retreiveAuthorization()
+	if err != nil {
+		return err
+	}
+
+	return g.saveAccessToken(tok)
+}
+
 func (g *Gmail) retreiveAuthorization() (*oauth2.Token, error) {
-	if cnf, err := g.loadConfig(); err == nil && !cnf.isZero() {
+	if cnf, err := g.loadConfig(); err == nil && !cnf.isZero() && cnf.covers(g.oauth.cnf.Scopes) {
 		return cnf.AccessToken, nil
 	}
 
@@ -155,20 +211,20 @@ func (g *Gmail) handleAuthorizationRedirect() (*oauth2.Token, error) {
 	return g.oauth.handleRedirect(context.Background(), nil, "/smoothie/gmail/authorization")
 }
 
-func (g *Gmail) listAndGetMessages(tok *oauth2.Token, params url.Values) ([]*gmailLib.Message, error) {
+func (g *Gmail) listAndGetMessages(ctx context.Context, tok *oauth2.Token, params url.Values) ([]*gmailLib.Message, error) {
 	assured := g.assureDefaultParams(params)
 	r := oauth2Req{
 		tok: tok,
 		req: req{method: http.MethodGet, url: g.endpoint("/users/me/messages"), params: assured},
 	}
 	var resp *gmailLib.ListMessagesResponse
-	if err := g.do(r, &resp); err != nil {
+	if err := g.do(ctx, r, &resp); err != nil {
 		g.resetAccessToken()
 		return nil, err
 	}
 	for _, m := range resp.Messages {
 		r.url = g.endpoint("/users/me/messages", m.Id)
-		if err := g.do(r, &m); err != nil {
+		if err := g.do(ctx, r, &m); err != nil {
 			g.resetAccessToken()
 			return nil, err
 		}
@@ -192,7 +248,7 @@ func (g *Gmail) saveAccessToken(tok *oauth2.Token) error {
 	if err != nil {
 		return err
 	}
-	cnf.AccessToken = tok
+	cnf.AccessToken, cnf.Scopes = tok, g.oauth.cnf.Scopes
 
 	return g.saveConfig(cnf)
 }
@@ -217,8 +273,8 @@ func (g *Gmail) assureDefaultParams(params url.Values) url.Values {
 	return assured
 }
 
-func (g *Gmail) do(r oauth2Req, dst interface{}) error {
-	resp, err := r.do(context.Background(), g.oauth.cnf)
+func (g *Gmail) do(ctx context.Context, r oauth2Req, dst interface{}) error {
+	resp, err := r.do(ctx, g.oauth.cnf)
 	if err != nil {
 		return err
 	}
@@ -31,14 +31,17 @@ func (m *Message) Adapt() *domain.Post {
 		User: &domain.User{
 			Name: header.to,
 		},
+		Title:     header.subject,
 		Text:      m.joinText(header),
+		URL:       fmt.Sprintf("https://mail.google.com/mail/#all/%s", m.ThreadId),
+		Tags:      m.LabelIds,
 		CreatedAt: time.Unix(0, m.InternalDate*int64(time.Millisecond)),
 	}
 }
 
 func (m *Message) joinText(h *header) string {
 	var b strings.Builder
-	fmt.Fprintf(&b, "%s %s", h.from, h.subject)
+	b.WriteString(h.from)
 	if h.mime != "text/plain" && h.mime != "multipart/alternative" {
 		return b.String()
 	}
@@ -0,0 +1,93 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestGmailAuthorize(t *testing.T) {
+	home, err := ioutil.TempDir("", "smoothie")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", home)
+	if err := createWorkspace(); err != nil {
+		t.Fatalf("failed to create workspace: %s", err)
+	}
+
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "smoothie", "token_type": "Bearer", "expires_in": 3600}`)
+	}))
+	defer tokenSrv.Close()
+
+	port, err := freePort()
+	if err != nil {
+		t.Fatalf("failed to find free port: %s", err)
+	}
+	presenter := &redirectingPresenter{
+		redirectURL: fmt.Sprintf("http://127.0.0.1:%d/smoothie/gmail/authorization", port),
+	}
+	g := NewGmail("id", "secret", presenter)
+	g.oauth.cnf.RedirectURL = presenter.redirectURL
+	g.oauth.cnf.Endpoint = oauth2.Endpoint{
+		AuthURL: tokenSrv.URL + "/auth", TokenURL: tokenSrv.URL + "/token",
+	}
+
+	if err := g.Authorize(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error by (*Gmail).Authorize: got %s, expect <nil>\n", err)
+	}
+	tok, err := g.retreiveAuthorization()
+	if err != nil {
+		t.Fatalf("unexpected error by (*Gmail).retreiveAuthorization: got %s, expect <nil>\n", err)
+	}
+	if tok.AccessToken != "smoothie" {
+		t.Errorf("unexpected access token by (*Gmail).retreiveAuthorization: got %s, expect smoothie\n", tok.AccessToken)
+	}
+	if presenter.shown != 1 {
+		t.Errorf("unexpected times auth url is shown: got %d, expect 1\n", presenter.shown)
+	}
+}
+
+type redirectingPresenter struct {
+	redirectURL string
+	shown       int
+}
+
+func (p *redirectingPresenter) ShowAuthURL(authURL string) {
+	p.shown++
+	parsed, _ := url.Parse(authURL)
+	dst := fmt.Sprintf("%s?code=code&state=%s", p.redirectURL, parsed.Query().Get("state"))
+	go func() {
+		for i := 0; i < 50; i++ {
+			resp, err := http.Get(dst)
+			if err == nil {
+				resp.Body.Close()
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,204 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+	"github.com/tomocy/smoothie/infra/hackernews"
+)
+
+const (
+	HackerNewsTop  = "top"
+	HackerNewsNew  = "new"
+	HackerNewsBest = "best"
+	HackerNewsAsk  = "ask"
+	HackerNewsShow = "show"
+)
+
+func NewHackerNews(list string) *HackerNews {
+	return &HackerNews{
+		list: list,
+	}
+}
+
+var hackernewsBaseURL = "https://hacker-news.firebaseio.com/v0"
+
+var hackernewsInterval = pollInterval{def: 5 * time.Minute, min: 30 * time.Second}
+
+type HackerNews struct {
+	list string
+}
+
+func (h *HackerNews) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
+	isCh, errCh := h.streamItems(ctx, hackernewsInterval.of(interval))
+	ch := make(chan domain.Posts)
+	go func() {
+		defer close(ch)
+		for is := range isCh {
+			ch <- is.Adapt()
+		}
+	}()
+
+	return ch, errCh
+}
+
+func (h *HackerNews) streamItems(ctx context.Context, interval time.Duration) (<-chan hackernews.Items, <-chan error) {
+	isCh, errCh := make(chan hackernews.Items), make(chan error)
+	go func() {
+		defer func() {
+			close(isCh)
+			close(errCh)
+		}()
+
+		seens := newSeenSet(hackernewsSeenSize)
+		h.fetchAndSendItems(ctx, seens, isCh, errCh)
+		for {
+			select {
+			case <-ctx.Done():
+				errCh <- ctx.Err()
+				return
+			case <-time.After(interval):
+				h.fetchAndSendItems(ctx, seens, isCh, errCh)
+			}
+		}
+	}()
+
+	return isCh, errCh
+}
+
+const hackernewsSeenSize = 1000
+
+func (h *HackerNews) fetchAndSendItems(ctx context.Context, seens *seenSet, isCh chan<- hackernews.Items, errCh chan<- error) {
+	ids, err := h.fetchStoryIDs(ctx)
+	if err != nil {
+		errCh <- err
+		return
+	}
+
+	var newIDs []int
+	for _, id := range ids {
+		if !seens.has(strconv.Itoa(id)) {
+			newIDs = append(newIDs, id)
+		}
+	}
+	if len(newIDs) <= 0 {
+		return
+	}
+
+	is, err := h.fetchItems(ctx, newIDs)
+	if err != nil {
+		errCh <- err
+		return
+	}
+	for _, item := range is {
+		seens.add(strconv.Itoa(item.ID))
+	}
+	if len(is) <= 0 {
+		return
+	}
+
+	isCh <- is
+}
+
+func (h *HackerNews) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	ids, err := h.fetchStoryIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch posts: %s", err)
+	}
+	is, err := h.fetchItems(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch posts: %s", err)
+	}
+
+	return is.Adapt(), nil
+}
+
+func (h *HackerNews) fetchStoryIDs(ctx context.Context) ([]int, error) {
+	var ids []int
+	if err := h.do(ctx, req{
+		method: http.MethodGet, url: h.endpoint(fmt.Sprintf("%sstories.json", h.list)),
+	}, &ids); err != nil {
+		return nil, err
+	}
+	if len(ids) > 30 {
+		ids = ids[:30]
+	}
+
+	return ids, nil
+}
+
+func (h *HackerNews) fetchItems(ctx context.Context, ids []int) (hackernews.Items, error) {
+	is, errs := make(hackernews.Items, len(ids)), make([]error, len(ids))
+	sem := make(chan struct{}, 10)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i, id int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			is[i], errs[i] = h.fetchItem(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	fetcheds := make(hackernews.Items, 0, len(is))
+	var lastErr error
+	for i, item := range is {
+		if errs[i] != nil {
+			lastErr = errs[i]
+			continue
+		}
+		if item == nil {
+			continue
+		}
+		fetcheds = append(fetcheds, item)
+	}
+	if len(fetcheds) <= 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return fetcheds, nil
+}
+
+func (h *HackerNews) fetchItem(ctx context.Context, id int) (*hackernews.Item, error) {
+	var item *hackernews.Item
+	if err := h.do(ctx, req{
+		method: http.MethodGet, url: h.endpoint("item", fmt.Sprintf("%d.json", id)),
+	}, &item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (h *HackerNews) do(ctx context.Context, r req, dst interface{}) error {
+	resp, err := r.do(ctx)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if http.StatusBadRequest <= resp.StatusCode {
+		return errors.New(resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+func (h *HackerNews) endpoint(ps ...string) string {
+	parsed, _ := url.Parse(hackernewsBaseURL)
+	ss := append([]string{parsed.Path}, ps...)
+	parsed.Path = filepath.Join(ss...)
+	return parsed.String()
+}
@@ -0,0 +1,75 @@
+package hackernews
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+	"github.com/tomocy/smoothie/infra/markup"
+)
+
+type Items []*Item
+
+func (is Items) Adapt() domain.Posts {
+	adapteds := make(domain.Posts, len(is))
+	for i, item := range is {
+		adapteds[i] = item.Adapt()
+	}
+
+	return adapteds
+}
+
+type Item struct {
+	ID          int      `json:"id"`
+	Type        string   `json:"type"`
+	By          string   `json:"by"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	Text        string   `json:"text"`
+	Score       int      `json:"score"`
+	Descendants int      `json:"descendants"`
+	Time        unixTime `json:"time"`
+}
+
+func (i *Item) Adapt() *domain.Post {
+	return &domain.Post{
+		ID:     fmt.Sprint(i.ID),
+		Driver: "hackernews",
+		User: &domain.User{
+			Name: i.By,
+		},
+		Title: i.Title,
+		Text:  i.joinText(),
+		URL:   i.joinURL(),
+		Link:  i.URL,
+		Metrics: domain.Metrics{
+			Replies: i.Descendants, Score: i.Score,
+		},
+		CreatedAt: time.Time(i.Time),
+	}
+}
+
+func (i *Item) joinText() string {
+	if text := markup.StripHTML(i.Text); text != "" {
+		return text
+	}
+
+	return i.URL
+}
+
+func (i *Item) joinURL() string {
+	return fmt.Sprintf("https://news.ycombinator.com/item?id=%d", i.ID)
+}
+
+type unixTime time.Time
+
+func (t *unixTime) UnmarshalJSON(data []byte) error {
+	sec, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	*t = unixTime(time.Unix(sec, 0).Local())
+
+	return nil
+}
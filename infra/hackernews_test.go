@@ -0,0 +1,95 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHackerNewsFetchPostsSkippingFailedItems(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/topstories.json":
+			fmt.Fprint(w, `[1, 2, 3]`)
+		case "/item/2.json":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/item/"), ".json")
+			fmt.Fprintf(w, `{"id": %s, "by": "tomocy", "title": "story %s", "time": 1565661600}`, id, id)
+		}
+	}))
+	defer srv.Close()
+	defer setHackerNewsBaseURL(srv.URL)()
+
+	ps, err := NewHackerNews(HackerNewsTop).FetchPosts(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error by (*HackerNews).FetchPosts: got %s, expect <nil>\n", err)
+	}
+	if len(ps) != 2 || ps[0].ID != "1" || ps[1].ID != "3" {
+		t.Errorf("unexpected posts by (*HackerNews).FetchPosts: got %d posts, expect 1 and 3\n", len(ps))
+	}
+}
+
+func TestHackerNewsStreamItemsOnlyOnce(t *testing.T) {
+	lists := []string{`[1, 2]`, `[2]`, `[1, 2]`}
+	var mu sync.Mutex
+	var polled int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path == "/topstories.json" {
+			fmt.Fprint(w, lists[polled%len(lists)])
+			polled++
+			return
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/item/"), ".json")
+		fmt.Fprintf(w, `{"id": %s, "by": "tomocy", "time": 1565661600}`, id)
+	}))
+	defer srv.Close()
+	defer setHackerNewsBaseURL(srv.URL)()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	isCh, errCh := NewHackerNews(HackerNewsTop).streamItems(ctx, 10*time.Millisecond)
+	counts := make(map[int]int)
+	for isCh != nil || errCh != nil {
+		select {
+		case is, ok := <-isCh:
+			if !ok {
+				isCh = nil
+				continue
+			}
+			for _, item := range is {
+				counts[item.ID]++
+			}
+		case err, ok := <-errCh:
+			if !ok {
+				errCh = nil
+				continue
+			}
+			if err != context.DeadlineExceeded {
+				t.Errorf("unexpected error by (*HackerNews).streamItems: got %s, expect %s\n", err, context.DeadlineExceeded)
+			}
+		}
+	}
+
+	for _, id := range []int{1, 2} {
+		if counts[id] != 1 {
+			t.Errorf("unexpected times item %d is streamed by (*HackerNews).streamItems: got %d, expect 1\n", id, counts[id])
+		}
+	}
+}
+
+func setHackerNewsBaseURL(url string) func() {
+	orig := hackernewsBaseURL
+	hackernewsBaseURL = url
+	return func() {
+		hackernewsBaseURL = orig
+	}
+}
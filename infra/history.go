@@ -0,0 +1,189 @@
+package infra
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tomocy/smoothie/domain"
+)
+
+const historyMaxPostsPerDriver = 1000
+
+func NewHistory(dir string) *History {
+	return &History{
+		dir: dir,
+	}
+}
+
+func HistoryName() string {
+	return filepath.Join(WorkspaceName(), "history")
+}
+
+type History struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func (h *History) SavePosts(d string, ps domain.Posts) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.MkdirAll(h.dir, 0700); err != nil {
+		return err
+	}
+
+	name := h.filename(d)
+	stored, err := h.loadPosts(name)
+	if err != nil {
+		return err
+	}
+	for _, p := range ps {
+		stored[p.ID] = p
+	}
+	prunePosts(stored, historyMaxPostsPerDriver)
+
+	return h.storePosts(name, stored)
+}
+
+func (h *History) FindPosts(q domain.HistoryQuery) (domain.Posts, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	names, err := filepath.Glob(filepath.Join(h.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var found domain.Posts
+	for _, name := range names {
+		d, err := h.driverOf(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(q.Drivers) > 0 && !matchDrivers(q.Drivers, d) {
+			continue
+		}
+		stored, err := h.loadPosts(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range stored {
+			if matchHistoryQuery(q, p) {
+				found = append(found, p)
+			}
+		}
+	}
+
+	found.SortByNewest()
+	if 0 < q.Limit && q.Limit < len(found) {
+		found = found[:q.Limit]
+	}
+
+	return found, nil
+}
+
+func (h *History) loadPosts(name string) (map[string]*domain.Post, error) {
+	loaded := make(map[string]*domain.Post)
+	src, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return loaded, nil
+		}
+		return nil, err
+	}
+	defer src.Close()
+
+	if err := json.NewDecoder(src).Decode(&loaded); err != nil {
+		return nil, err
+	}
+
+	return loaded, nil
+}
+
+func (h *History) storePosts(name string, ps map[string]*domain.Post) error {
+	tmp, err := ioutil.TempFile(h.dir, "history")
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(tmp).Encode(ps); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+func (h *History) filename(d string) string {
+	return filepath.Join(h.dir, url.PathEscape(d)+".json")
+}
+
+func (h *History) driverOf(name string) (string, error) {
+	return url.PathUnescape(strings.TrimSuffix(filepath.Base(name), ".json"))
+}
+
+func prunePosts(ps map[string]*domain.Post, max int) {
+	if len(ps) <= max {
+		return
+	}
+
+	ids := make([]string, 0, len(ps))
+	for id := range ps {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ps[ids[i]].CreatedAt.After(ps[ids[j]].CreatedAt)
+	})
+	for _, id := range ids[max:] {
+		delete(ps, id)
+	}
+}
+
+func matchHistoryQuery(q domain.HistoryQuery, p *domain.Post) bool {
+	if q.User != "" && !matchUser(q.User, p.User) {
+		return false
+	}
+	if !q.Since.IsZero() && p.CreatedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !p.CreatedAt.Before(q.Until) {
+		return false
+	}
+	if q.Text != "" && !containsFold(p.Title, q.Text) && !containsFold(p.Text, q.Text) {
+		return false
+	}
+
+	return true
+}
+
+func matchDrivers(ds []string, d string) bool {
+	for _, candidate := range ds {
+		if d == candidate || strings.HasPrefix(d, candidate+":") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchUser(name string, u *domain.User) bool {
+	if u == nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Name, name) || strings.EqualFold(u.Username, name)
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
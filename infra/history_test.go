@@ -0,0 +1,150 @@
+package infra
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+)
+
+func TestHistoryFindPosts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "smoothie")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	ps := domain.Posts{
+		{ID: "1", Driver: "twitter", User: &domain.User{Name: "tomocy"}, Text: "I love Golang", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "2", Driver: "twitter", User: &domain.User{Name: "gopher"}, Text: "golang is fun", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "1", Driver: "github event", User: &domain.User{Name: "tomocy"}, Title: "golang/go", CreatedAt: now.Add(-1 * time.Hour)},
+		{ID: "3", Driver: "reddit", User: &domain.User{Name: "tomocy"}, Text: "rust", CreatedAt: now},
+	}
+	h := NewHistory(dir)
+	saveds := map[string]domain.Posts{
+		"twitter":              ps[:2],
+		"github:events:tomocy": ps[2:3],
+		"reddit":               ps[3:],
+	}
+	for d, ps := range saveds {
+		if err := h.SavePosts(d, ps); err != nil {
+			t.Fatalf("failed to save posts: %s", err)
+		}
+	}
+	if err := h.SavePosts("twitter", ps[1:2]); err != nil {
+		t.Fatalf("failed to save posts: %s", err)
+	}
+
+	tests := map[string]struct {
+		q         domain.HistoryQuery
+		expecteds domain.Posts
+	}{
+		"all": {
+			expecteds: domain.Posts{ps[3], ps[2], ps[1], ps[0]},
+		},
+		"drivers": {
+			q:         domain.HistoryQuery{Drivers: []string{"github:events", "reddit"}},
+			expecteds: domain.Posts{ps[3], ps[2]},
+		},
+		"driver with args": {
+			q:         domain.HistoryQuery{Drivers: []string{"github:events:tomocy"}},
+			expecteds: domain.Posts{ps[2]},
+		},
+		"driver with other args": {
+			q: domain.HistoryQuery{Drivers: []string{"github:issues:golang/go"}},
+		},
+		"driver of partial name": {
+			q: domain.HistoryQuery{Drivers: []string{"git"}},
+		},
+		"user": {
+			q:         domain.HistoryQuery{User: "gopher"},
+			expecteds: domain.Posts{ps[1]},
+		},
+		"time range": {
+			q:         domain.HistoryQuery{Since: now.Add(-2 * time.Hour), Until: now},
+			expecteds: domain.Posts{ps[2], ps[1]},
+		},
+		"text": {
+			q:         domain.HistoryQuery{Text: "GOLANG"},
+			expecteds: domain.Posts{ps[2], ps[1], ps[0]},
+		},
+		"limit": {
+			q:         domain.HistoryQuery{Text: "golang", Limit: 1},
+			expecteds: domain.Posts{ps[2]},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actuals, err := h.FindPosts(test.q)
+			if err != nil {
+				t.Fatalf("unexpected error from FindPosts: %s", err)
+			}
+			if err := assertPosts(actuals, test.expecteds); err != nil {
+				t.Errorf("unexpected posts from FindPosts: %s", err)
+			}
+		})
+	}
+}
+
+func TestHistorySavePostsByDriverWithArgs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "smoothie")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	ps := domain.Posts{
+		{ID: "1", Driver: "mastodon", User: &domain.User{Name: "tomocy"}, Text: "from mastodon.social", CreatedAt: now},
+		{ID: "1", Driver: "mastodon", User: &domain.User{Name: "gopher"}, Text: "from fosstodon.org", CreatedAt: now.Add(-1 * time.Hour)},
+	}
+	h := NewHistory(dir)
+	if err := h.SavePosts("mastodon:home:mastodon.social", ps[:1]); err != nil {
+		t.Fatalf("failed to save posts: %s", err)
+	}
+	if err := h.SavePosts("mastodon:home:fosstodon.org", ps[1:]); err != nil {
+		t.Fatalf("failed to save posts: %s", err)
+	}
+
+	actuals, err := h.FindPosts(domain.HistoryQuery{Drivers: []string{"mastodon"}})
+	if err != nil {
+		t.Fatalf("unexpected error from FindPosts: %s", err)
+	}
+	if err := assertPosts(actuals, ps); err != nil {
+		t.Errorf("unexpected posts from FindPosts: %s", err)
+	}
+}
+
+func TestHistorySavePostsPrunesOldests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "smoothie")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	ps := make(domain.Posts, historyMaxPostsPerDriver+2)
+	for i := range ps {
+		ps[i] = &domain.Post{ID: fmt.Sprint(i), Driver: "twitter", User: &domain.User{Name: "tomocy"}, CreatedAt: now.Add(time.Duration(-i) * time.Minute)}
+	}
+	h := NewHistory(dir)
+	if err := h.SavePosts("twitter", ps[2:]); err != nil {
+		t.Fatalf("failed to save posts: %s", err)
+	}
+	if err := h.SavePosts("twitter", ps[:2]); err != nil {
+		t.Fatalf("failed to save posts: %s", err)
+	}
+
+	actuals, err := h.FindPosts(domain.HistoryQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error from FindPosts: %s", err)
+	}
+	if err := assertPosts(actuals, ps[:historyMaxPostsPerDriver]); err != nil {
+		t.Errorf("unexpected posts from FindPosts: %s", err)
+	}
+}
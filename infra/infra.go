@@ -1,6 +1,7 @@
 package infra
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/garyburd/go-oauth/oauth"
 	"golang.org/x/oauth2"
@@ -34,11 +36,7 @@ func createWorkspace() error {
 		return nil
 	}
 
-	dir := WorkspaceName()
-	if err := os.RemoveAll(dir); err != nil {
-		return err
-	}
-	if err := os.MkdirAll(dir, 0700); err != nil {
+	if err := os.MkdirAll(WorkspaceName(), 0700); err != nil {
 		return err
 	}
 
@@ -51,17 +49,70 @@ func createWorkspace() error {
 	return json.NewEncoder(f).Encode(config{})
 }
 
+func DeleteCredentials() error {
+	if err := os.Remove(configFilename()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return createWorkspace()
+}
+
+type pollInterval struct {
+	def, min time.Duration
+}
+
+func (i pollInterval) of(d time.Duration) time.Duration {
+	if d <= 0 {
+		return i.def
+	}
+	if d < i.min {
+		return i.min
+	}
+
+	return d
+}
+
+func newSeenSet(size int) *seenSet {
+	return &seenSet{
+		size: size,
+		keys: make(map[string]bool),
+	}
+}
+
+type seenSet struct {
+	size  int
+	keys  map[string]bool
+	order []string
+}
+
+func (s *seenSet) has(key string) bool {
+	return s.keys[key]
+}
+
+func (s *seenSet) add(key string) {
+	if s.keys[key] {
+		return
+	}
+
+	s.keys[key] = true
+	s.order = append(s.order, key)
+	if len(s.order) > s.size {
+		delete(s.keys, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
 type oauthReq struct {
 	req
 	cred *oauth.Credentials
 }
 
-func (r *oauthReq) do(client oauth.Client) (*http.Response, error) {
+func (r *oauthReq) do(ctx context.Context, client oauth.Client) (*http.Response, error) {
 	if r.method != http.MethodGet {
-		return client.Post(http.DefaultClient, r.cred, r.url, r.params)
+		return client.PostContext(ctx, r.cred, r.url, r.params)
 	}
 
-	return client.Get(http.DefaultClient, r.cred, r.url, r.params)
+	return client.GetContext(ctx, r.cred, r.url, r.params)
 }
 
 type oauth2Req struct {
@@ -71,36 +122,81 @@ type oauth2Req struct {
 
 func (r *oauth2Req) do(ctx context.Context, cnf oauth2.Config) (*http.Response, error) {
 	client := cnf.Client(ctx, r.tok)
+	if r.method != http.MethodGet && r.body != nil {
+		req, err := r.newJSONRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		return client.Do(req.WithContext(ctx))
+	}
 	if r.method != http.MethodGet {
-		return client.PostForm(r.url, r.params)
+		req, err := http.NewRequest(http.MethodPost, r.url, strings.NewReader(r.params.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		return client.Do(req.WithContext(ctx))
 	}
 
-	parsed, err := url.Parse(r.url)
+	joined, err := r.joinURLWithEncodedQuery()
 	if err != nil {
 		return nil, err
 	}
-	if r.params != nil {
-		parsed.RawQuery = r.params.Encode()
+	req, err := http.NewRequest(http.MethodGet, joined, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return client.Get(parsed.String())
+	return client.Do(req.WithContext(ctx))
 }
 
 type req struct {
 	method, url string
 	header      http.Header
 	params      url.Values
+	body        interface{}
 }
 
-func (r *req) do() (*http.Response, error) {
+func (r *req) do(ctx context.Context) (*http.Response, error) {
 	if r.method != http.MethodGet {
-		return r.postForm()
+		if r.body != nil {
+			return r.sendJSON(ctx)
+		}
+		return r.postForm(ctx)
 	}
 
-	return r.get()
+	return r.get(ctx)
 }
 
-func (r *req) postForm() (*http.Response, error) {
+func (r *req) sendJSON(ctx context.Context) (*http.Response, error) {
+	req, err := r.newJSONRequest()
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range r.header {
+		req.Header[k] = vs
+	}
+
+	return http.DefaultClient.Do(req.WithContext(ctx))
+}
+
+func (r *req) newJSONRequest() (*http.Request, error) {
+	encoded, err := json.Marshal(r.body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(r.method, r.url, bytes.NewReader(encoded))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+func (r *req) postForm(ctx context.Context) (*http.Response, error) {
 	req, err := http.NewRequest(http.MethodPost, r.url, strings.NewReader(r.params.Encode()))
 	if err != nil {
 		return nil, err
@@ -110,10 +206,10 @@ func (r *req) postForm() (*http.Response, error) {
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	return http.DefaultClient.Do(req)
+	return http.DefaultClient.Do(req.WithContext(ctx))
 }
 
-func (r *req) get() (*http.Response, error) {
+func (r *req) get(ctx context.Context) (*http.Response, error) {
 	joined, err := r.joinURLWithEncodedQuery()
 	if err != nil {
 		return nil, err
@@ -127,7 +223,7 @@ func (r *req) get() (*http.Response, error) {
 		req.Header = r.header
 	}
 
-	return http.DefaultClient.Do(req)
+	return http.DefaultClient.Do(req.WithContext(ctx))
 }
 
 func (r *req) joinURLWithEncodedQuery() (string, error) {
@@ -142,6 +238,15 @@ func (r *req) joinURLWithEncodedQuery() (string, error) {
 	return parsed.String(), nil
 }
 
+func splitTitleAndBody(text string) (string, string) {
+	splited := strings.SplitN(strings.TrimSpace(text), "\n", 2)
+	if len(splited) < 2 {
+		return splited[0], ""
+	}
+
+	return strings.TrimSpace(splited[0]), strings.TrimSpace(splited[1])
+}
+
 type resp struct {
 	header http.Header
 	body   interface{}
@@ -163,20 +268,14 @@ func (m *oauthManager) authURL(params url.Values) (string, error) {
 }
 
 func (m *oauthManager) handleRedirect(ctx context.Context, path string) (*oauth.Credentials, error) {
-	credCh, errCh := make(chan *oauth.Credentials), make(chan error)
-	var srv http.Server
+	credCh, errCh := make(chan *oauth.Credentials, 1), make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.Handle(path, m.handlerForRedirect(ctx, credCh, errCh))
+	srv := &http.Server{
+		Handler: mux,
+	}
 	defer srv.Shutdown(ctx)
-	go func() {
-		defer func() {
-			close(credCh)
-			close(errCh)
-		}()
-
-		http.Handle(path, m.handlerForRedirect(ctx, credCh, errCh))
-		if err := srv.ListenAndServe(); err != nil {
-			errCh <- err
-		}
-	}()
+	go serveRedirect(srv, errCh)
 
 	select {
 	case cred := <-credCh:
@@ -193,14 +292,39 @@ func (m *oauthManager) handlerForRedirect(ctx context.Context, credCh chan<- *oa
 		token, _, err := m.client.RequestTokenContext(ctx, m.temp, v)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
-			errCh <- err
+			trySendError(errCh, err)
 			return
 		}
 
-		credCh <- token
+		select {
+		case credCh <- token:
+		default:
+		}
 	})
 }
 
+func redirectAddr(redirectURL string) string {
+	parsed, err := url.Parse(redirectURL)
+	if err != nil || parsed.Port() == "" {
+		return ""
+	}
+
+	return ":" + parsed.Port()
+}
+
+func serveRedirect(srv *http.Server, errCh chan<- error) {
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		trySendError(errCh, err)
+	}
+}
+
+func trySendError(errCh chan<- error, err error) {
+	select {
+	case errCh <- err:
+	default:
+	}
+}
+
 type oauth2Manager struct {
 	state string
 	cnf   oauth2.Config
@@ -212,20 +336,15 @@ func (m *oauth2Manager) authURL(params ...oauth2.AuthCodeOption) string {
 }
 
 func (m *oauth2Manager) handleRedirect(ctx context.Context, params []oauth2.AuthCodeOption, path string) (*oauth2.Token, error) {
-	tokCh, errCh := make(chan *oauth2.Token), make(chan error)
-	var srv http.Server
+	tokCh, errCh := make(chan *oauth2.Token, 1), make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.Handle(path, m.handlerForRedirect(ctx, params, tokCh, errCh))
+	srv := &http.Server{
+		Addr:    redirectAddr(m.cnf.RedirectURL),
+		Handler: mux,
+	}
 	defer srv.Shutdown(ctx)
-	go func() {
-		defer func() {
-			close(tokCh)
-			close(errCh)
-		}()
-
-		http.Handle(path, m.handlerForRedirect(ctx, params, tokCh, errCh))
-		if err := srv.ListenAndServe(); err != nil {
-			errCh <- err
-		}
-	}()
+	go serveRedirect(srv, errCh)
 
 	select {
 	case tok := <-tokCh:
@@ -241,18 +360,21 @@ func (m *oauth2Manager) handlerForRedirect(ctx context.Context, params []oauth2.
 		state, code := q.Get("state"), q.Get("code")
 		if err := m.checkState(state); err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			errCh <- err
+			trySendError(errCh, err)
 			return
 		}
 
 		tok, err := m.cnf.Exchange(ctx, code, params...)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
-			errCh <- err
+			trySendError(errCh, err)
 			return
 		}
 
-		tokCh <- tok
+		select {
+		case tokCh <- tok:
+		default:
+		}
 	})
 }
 
@@ -306,10 +428,11 @@ func saveConfig(cnf config) error {
 }
 
 type config struct {
-	Gmail   oauth2Config `json:"gmail"`
-	Tumblr  oauthConfig  `json:"tumblr"`
-	Twitter oauthConfig  `json:"twitter"`
-	Reddit  oauth2Config `json:"reddit"`
+	Gmail    oauth2Config              `json:"gmail"`
+	Tumblr   oauthConfig               `json:"tumblr"`
+	Twitter  oauthConfig               `json:"twitter"`
+	Reddit   oauth2Config              `json:"reddit"`
+	Mastodon map[string]mastodonConfig `json:"mastodons"`
 }
 
 type oauthConfig struct {
@@ -326,6 +449,7 @@ func (c *oauthConfig) isZero() bool {
 
 type oauth2Config struct {
 	AccessToken *oauth2.Token `json:"access_token"`
+	Scopes      []string      `json:"scopes"`
 }
 
 func (c *oauth2Config) isZero() bool {
@@ -336,6 +460,26 @@ func (c *oauth2Config) isZero() bool {
 	return true
 }
 
+func (c *oauth2Config) covers(scopes []string) bool {
+	granteds := make(map[string]bool)
+	for _, s := range c.Scopes {
+		granteds[s] = true
+	}
+	for _, s := range scopes {
+		if !granteds[s] {
+			return false
+		}
+	}
+
+	return true
+}
+
+type mastodonConfig struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	oauth2Config
+}
+
 func configFilename() string {
 	return filepath.Join(WorkspaceName(), "config.json")
 }
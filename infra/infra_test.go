@@ -0,0 +1,76 @@
+package infra
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garyburd/go-oauth/oauth"
+)
+
+func TestWorkspaceKeepsUserData(t *testing.T) {
+	home, err := ioutil.TempDir("", "smoothie")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", home)
+
+	if err := os.MkdirAll(WorkspaceName(), 0700); err != nil {
+		t.Fatalf("failed to create workspace: %s", err)
+	}
+	feeds := filepath.Join(WorkspaceName(), "feeds.json")
+	if err := ioutil.WriteFile(feeds, []byte(`{"profiles": {}}`), 0600); err != nil {
+		t.Fatalf("failed to write feeds: %s", err)
+	}
+
+	if err := createWorkspace(); err != nil {
+		t.Fatalf("unexpected error by createWorkspace: got %s, expect <nil>\n", err)
+	}
+	if _, err := os.Stat(configFilename()); err != nil {
+		t.Errorf("unexpected config by createWorkspace: got %s, expect it to be created\n", err)
+	}
+	if _, err := os.Stat(feeds); err != nil {
+		t.Errorf("unexpected feeds by createWorkspace: got %s, expect it to be kept\n", err)
+	}
+
+	if err := saveConfig(config{Twitter: oauthConfig{AccessCredentials: &oauth.Credentials{Token: "token"}}}); err != nil {
+		t.Fatalf("failed to save config: %s", err)
+	}
+	if err := DeleteCredentials(); err != nil {
+		t.Fatalf("unexpected error by DeleteCredentials: got %s, expect <nil>\n", err)
+	}
+	cnf, err := loadConfig()
+	if err != nil {
+		t.Fatalf("failed to load config: %s", err)
+	}
+	if cnf.Twitter.AccessCredentials != nil {
+		t.Errorf("unexpected credentials after DeleteCredentials: got %v, expect <nil>\n", cnf.Twitter.AccessCredentials)
+	}
+	if _, err := os.Stat(feeds); err != nil {
+		t.Errorf("unexpected feeds by DeleteCredentials: got %s, expect it to be kept\n", err)
+	}
+}
+
+func TestOAuth2ConfigCovers(t *testing.T) {
+	tests := map[string]struct {
+		granteds, scopes []string
+		expected         bool
+	}{
+		"same":         {granteds: []string{"read", "write"}, scopes: []string{"read", "write"}, expected: true},
+		"more granted": {granteds: []string{"read", "write"}, scopes: []string{"read"}, expected: true},
+		"less granted": {granteds: []string{"read"}, scopes: []string{"read", "write"}},
+		"not granted":  {scopes: []string{"read"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cnf := oauth2Config{Scopes: test.granteds}
+			if actual := cnf.covers(test.scopes); actual != test.expected {
+				t.Errorf("unexpected result of (*oauth2Config).covers: got %t, expect %t\n", actual, test.expected)
+			}
+		})
+	}
+}
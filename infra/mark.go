@@ -0,0 +1,90 @@
+package infra
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tomocy/smoothie/domain"
+)
+
+func NewMarkStore(name string) *MarkStore {
+	return &MarkStore{
+		name: name,
+	}
+}
+
+func MarkStoreName() string {
+	return filepath.Join(WorkspaceName(), "marks.json")
+}
+
+type MarkStore struct {
+	mu   sync.Mutex
+	name string
+}
+
+func (s *MarkStore) FindMarks(d string) (domain.Marks, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loaded, err := s.load()
+	if err != nil {
+		return domain.Marks{}, err
+	}
+
+	return loaded[d], nil
+}
+
+func (s *MarkStore) SaveMarks(d string, ms domain.Marks) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loaded, err := s.load()
+	if err != nil {
+		return err
+	}
+	loaded[d] = ms
+
+	return s.store(loaded)
+}
+
+func (s *MarkStore) load() (map[string]domain.Marks, error) {
+	loaded := make(map[string]domain.Marks)
+	src, err := os.Open(s.name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return loaded, nil
+		}
+		return nil, err
+	}
+	defer src.Close()
+
+	if err := json.NewDecoder(src).Decode(&loaded); err != nil {
+		return nil, err
+	}
+
+	return loaded, nil
+}
+
+func (s *MarkStore) store(ms map[string]domain.Marks) error {
+	if err := os.MkdirAll(filepath.Dir(s.name), 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.name), "marks")
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(tmp).Encode(ms); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.name)
+}
@@ -0,0 +1,19 @@
+package markup
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	lineBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</p>\s*<p>|<p>`)
+	tags       = regexp.MustCompile(`<[^>]*>`)
+)
+
+func StripHTML(s string) string {
+	broken := lineBreaks.ReplaceAllString(s, "\n")
+	stripped := tags.ReplaceAllString(broken, "")
+
+	return strings.TrimSpace(html.UnescapeString(stripped))
+}
@@ -0,0 +1,25 @@
+package markup
+
+import "testing"
+
+func TestStripHTML(t *testing.T) {
+	tests := map[string]struct {
+		src, expected string
+	}{
+		"paragraphs":            {src: "<p>one</p><p>two</p>", expected: "one\ntwo"},
+		"paragraphs with space": {src: "<p>one</p>\n  <p>two</p>", expected: "one\ntwo"},
+		"unclosed paragraphs":   {src: "one<p>two<p>three", expected: "one\ntwo\nthree"},
+		"line breaks":           {src: "one<br>two<BR/>three<br />four", expected: "one\ntwo\nthree\nfour"},
+		"tags":                  {src: `<a href="https://example.com">link</a> <span class="tag">#go</span>`, expected: "link #go"},
+		"entities":              {src: "&lt;go&gt; &amp; &#x27;smoothie&#x27;", expected: "<go> & 'smoothie'"},
+		"plain":                 {src: "  plain  ", expected: "plain"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := StripHTML(test.src); actual != test.expected {
+				t.Errorf("unexpected text by StripHTML: got %q, expect %q\n", actual, test.expected)
+			}
+		})
+	}
+}
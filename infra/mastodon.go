@@ -0,0 +1,422 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+	"github.com/tomocy/smoothie/infra/mastodon"
+	"golang.org/x/oauth2"
+)
+
+const (
+	MastodonHome   = "home"
+	MastodonLocal  = "local"
+	MastodonPublic = "public"
+	MastodonTag    = "tag"
+)
+
+func NewMastodon(timeline string, presenter authURLPresenter) *Mastodon {
+	return &Mastodon{
+		timeline:  timeline,
+		presenter: presenter,
+	}
+}
+
+var (
+	mastodonInterval    = pollInterval{def: time.Minute, min: 30 * time.Second}
+	mastodonScopes      = []string{"read", "write"}
+	mastodonRedirectURL = "http://localhost/smoothie/mastodon/authorization"
+)
+
+type Mastodon struct {
+	timeline  string
+	presenter authURLPresenter
+}
+
+func (m *Mastodon) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
+	parsed := m.parseArgs(args)
+	ssCh, errCh := m.streamStatuses(ctx, mastodonInterval.of(interval), parsed, nil)
+	ch := make(chan domain.Posts)
+	go func() {
+		defer close(ch)
+		for ss := range ssCh {
+			ch <- ss.Adapt()
+		}
+	}()
+
+	return ch, errCh
+}
+
+func (m *Mastodon) streamStatuses(ctx context.Context, interval time.Duration, as mastodonArgs, params url.Values) (<-chan mastodon.Statuses, <-chan error) {
+	ssCh, errCh := make(chan mastodon.Statuses), make(chan error)
+	go func() {
+		defer func() {
+			close(ssCh)
+			close(errCh)
+		}()
+
+		lastID := m.fetchAndSendStatuses(ctx, as, params, ssCh, errCh)
+		for {
+			select {
+			case <-ctx.Done():
+				errCh <- ctx.Err()
+				return
+			case <-time.After(interval):
+				if lastID != "" {
+					if params == nil {
+						params = make(url.Values)
+					}
+					params.Set("since_id", lastID)
+				}
+				if id := m.fetchAndSendStatuses(ctx, as, params, ssCh, errCh); id != "" {
+					lastID = id
+				}
+			}
+		}
+	}()
+
+	return ssCh, errCh
+}
+
+func (m *Mastodon) fetchAndSendStatuses(
+	ctx context.Context, as mastodonArgs, params url.Values,
+	ssCh chan<- mastodon.Statuses, errCh chan<- error,
+) string {
+	ss, err := m.fetchStatuses(ctx, as, params)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+		default:
+			errCh <- err
+		}
+		return ""
+	}
+	if len(ss) <= 0 {
+		return ""
+	}
+
+	select {
+	case <-ctx.Done():
+	default:
+		ssCh <- ss
+	}
+	return ss[0].ID
+}
+
+func (m *Mastodon) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	parsed := m.parseArgs(args)
+	ss, err := m.fetchStatuses(ctx, parsed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch posts: %s", err)
+	}
+
+	return ss.Adapt(), nil
+}
+
+func (m *Mastodon) fetchStatuses(ctx context.Context, as mastodonArgs, params url.Values) (mastodon.Statuses, error) {
+	if err := as.validate(m.timeline); err != nil {
+		return nil, err
+	}
+
+	cnf, tok, err := m.retreiveAuthorization(ctx, as.host)
+	if err != nil {
+		return nil, err
+	}
+
+	assured := m.assureDefaultParams(params)
+	dst := m.endpoint(as.host, "/timelines/home")
+	switch m.timeline {
+	case MastodonLocal:
+		dst = m.endpoint(as.host, "/timelines/public")
+		assured.Set("local", "true")
+	case MastodonPublic:
+		dst = m.endpoint(as.host, "/timelines/public")
+	case MastodonTag:
+		dst = m.endpoint(as.host, "/timelines/tag", as.tag)
+	}
+
+	var ss mastodon.Statuses
+	if err := m.do(ctx, cnf, oauth2Req{
+		tok: tok,
+		req: req{method: http.MethodGet, url: dst, params: assured},
+	}, &ss); err != nil {
+		return nil, err
+	}
+	if err := m.saveAccessToken(as.host, tok); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+func (m *Mastodon) CreatePost(ctx context.Context, args []string, text string) (*domain.Post, error) {
+	s, err := m.postStatus(ctx, m.parseArgs(args), url.Values{
+		"status": []string{text},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create post: %s", err)
+	}
+
+	return s.Adapt(), nil
+}
+
+func (m *Mastodon) Reply(ctx context.Context, args []string, id, text string) (*domain.Post, error) {
+	s, err := m.postStatus(ctx, m.parseArgs(args), url.Values{
+		"status": []string{text}, "in_reply_to_id": []string{id},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reply: %s", err)
+	}
+
+	return s.Adapt(), nil
+}
+
+func (m *Mastodon) React(ctx context.Context, args []string, id string, r domain.Reaction) error {
+	var action string
+	switch r {
+	case domain.ReactionLike:
+		action = "favourite"
+	case domain.ReactionBoost:
+		action = "reblog"
+	default:
+		return &domain.UnsupportedReactionError{Reaction: r}
+	}
+
+	as := m.parseArgs(args)
+	if as.host == "" {
+		return errors.New("host should be specified")
+	}
+	cnf, tok, err := m.retreiveAuthorization(ctx, as.host)
+	if err != nil {
+		return err
+	}
+
+	var s *mastodon.Status
+	if err := m.do(ctx, cnf, oauth2Req{
+		tok: tok,
+		req: req{method: http.MethodPost, url: m.endpoint(as.host, "/statuses", id, action)},
+	}, &s); err != nil {
+		return fmt.Errorf("failed to react: %s", err)
+	}
+
+	return m.saveAccessToken(as.host, tok)
+}
+
+func (m *Mastodon) postStatus(ctx context.Context, as mastodonArgs, params url.Values) (*mastodon.Status, error) {
+	if as.host == "" {
+		return nil, errors.New("host should be specified")
+	}
+
+	cnf, tok, err := m.retreiveAuthorization(ctx, as.host)
+	if err != nil {
+		return nil, err
+	}
+
+	var s *mastodon.Status
+	if err := m.do(ctx, cnf, oauth2Req{
+		tok: tok,
+		req: req{method: http.MethodPost, url: m.endpoint(as.host, "/statuses"), params: params},
+	}, &s); err != nil {
+		return nil, err
+	}
+	if err := m.saveAccessToken(as.host, tok); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (m *Mastodon) Authorize(ctx context.Context, args []string) error {
+	parsed := m.parseArgs(args)
+	if err := parsed.validate(m.timeline); err != nil {
+		return err
+	}
+
+	_, tok, err := m.retreiveAuthorization(ctx, parsed.host)
+	if err != nil {
+		return err
+	}
+
+	return m.saveAccessToken(parsed.host, tok)
+}
+
+func (m *Mastodon) retreiveAuthorization(ctx context.Context, host string) (oauth2.Config, *oauth2.Token, error) {
+	loaded, err := m.loadConfig(host)
+	if err != nil {
+		return oauth2.Config{}, nil, err
+	}
+	if loaded.ClientID == "" {
+		app, err := m.registerApp(ctx, host)
+		if err != nil {
+			return oauth2.Config{}, nil, fmt.Errorf("failed to register app to %s: %s", host, err)
+		}
+		loaded.ClientID, loaded.ClientSecret = app.ClientID, app.ClientSecret
+		if err := m.saveConfig(host, loaded); err != nil {
+			return oauth2.Config{}, nil, err
+		}
+	}
+
+	cnf := m.oauthConfig(host, loaded)
+	if !loaded.isZero() && loaded.covers(mastodonScopes) {
+		return cnf, loaded.AccessToken, nil
+	}
+
+	manager := &oauth2Manager{
+		cnf: cnf,
+	}
+	url := manager.authURL()
+	m.presenter.ShowAuthURL(url)
+
+	tok, err := manager.handleRedirect(context.Background(), nil, "/smoothie/mastodon/authorization")
+	if err != nil {
+		return oauth2.Config{}, nil, err
+	}
+
+	return cnf, tok, nil
+}
+
+func (m *Mastodon) registerApp(ctx context.Context, host string) (*mastodon.App, error) {
+	resp, err := (&req{
+		method: http.MethodPost, url: m.endpoint(host, "/apps"),
+		params: url.Values{
+			"client_name":   []string{"smoothie"},
+			"redirect_uris": []string{mastodonRedirectURL},
+			"scopes":        []string{strings.Join(mastodonScopes, " ")},
+		},
+	}).do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if http.StatusBadRequest <= resp.StatusCode {
+		return nil, errors.New(resp.Status)
+	}
+
+	var app *mastodon.App
+	if err := json.NewDecoder(resp.Body).Decode(&app); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+func (m *Mastodon) oauthConfig(host string, cnf mastodonConfig) oauth2.Config {
+	return oauth2.Config{
+		ClientID: cnf.ClientID, ClientSecret: cnf.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   fmt.Sprintf("https://%s/oauth/authorize", host),
+			TokenURL:  fmt.Sprintf("https://%s/oauth/token", host),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		RedirectURL: mastodonRedirectURL,
+		Scopes:      mastodonScopes,
+	}
+}
+
+func (m *Mastodon) loadConfig(host string) (mastodonConfig, error) {
+	cnf, err := loadConfig()
+	if err != nil {
+		return mastodonConfig{}, err
+	}
+
+	return cnf.Mastodon[host], nil
+}
+
+func (m *Mastodon) assureDefaultParams(params url.Values) url.Values {
+	assured := params
+	if assured == nil {
+		assured = make(url.Values)
+	}
+	assured.Set("limit", "40")
+
+	return assured
+}
+
+func (m *Mastodon) do(ctx context.Context, cnf oauth2.Config, r oauth2Req, dst interface{}) error {
+	resp, err := r.do(ctx, cnf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if http.StatusBadRequest <= resp.StatusCode {
+		return errors.New(resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+func (m *Mastodon) saveAccessToken(host string, tok *oauth2.Token) error {
+	loaded, err := m.loadConfig(host)
+	if err != nil {
+		return err
+	}
+	loaded.AccessToken, loaded.Scopes = tok, mastodonScopes
+
+	return m.saveConfig(host, loaded)
+}
+
+func (m *Mastodon) saveConfig(host string, cnf mastodonConfig) error {
+	loaded, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if loaded.Mastodon == nil {
+		loaded.Mastodon = make(map[string]mastodonConfig)
+	}
+	loaded.Mastodon[host] = cnf
+
+	return saveConfig(loaded)
+}
+
+func (m *Mastodon) endpoint(host string, ps ...string) string {
+	parsed := &url.URL{
+		Scheme: "https", Host: host, Path: "/api/v1",
+	}
+	ss := append([]string{parsed.Path}, ps...)
+	parsed.Path = filepath.Join(ss...)
+	return parsed.String()
+}
+
+func (m *Mastodon) parseArgs(args []string) mastodonArgs {
+	var parsed mastodonArgs
+	parsed.parse(args)
+
+	return parsed
+}
+
+type mastodonArgs struct {
+	host, tag string
+}
+
+func (as *mastodonArgs) parse(args []string) {
+	if len(args) <= 0 {
+		return
+	}
+	as.host = args[0]
+
+	if len(args) <= 1 {
+		return
+	}
+	as.tag = args[1]
+}
+
+func (as *mastodonArgs) validate(timeline string) error {
+	if as.host == "" {
+		return errors.New("host of mastodon instance is not specified")
+	}
+	if timeline == MastodonTag && as.tag == "" {
+		return errors.New("tag of mastodon timeline is not specified")
+	}
+
+	return nil
+}
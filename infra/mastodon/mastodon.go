@@ -0,0 +1,123 @@
+package mastodon
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tomocy/smoothie/domain"
+	"github.com/tomocy/smoothie/infra/markup"
+)
+
+type Statuses []*Status
+
+func (ss Statuses) Adapt() domain.Posts {
+	adapteds := make(domain.Posts, len(ss))
+	for i, s := range ss {
+		adapteds[i] = s.Adapt()
+	}
+
+	return adapteds
+}
+
+type Status struct {
+	ID               string   `json:"id"`
+	Account          *Account `json:"account"`
+	Content          string   `json:"content"`
+	SpoilerText      string   `json:"spoiler_text"`
+	URL              string   `json:"url"`
+	Reblog           *Status  `json:"reblog"`
+	InReplyToID      string   `json:"in_reply_to_id"`
+	MediaAttachments []*struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"media_attachments"`
+	Tags []*struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	FavouritesCount int       `json:"favourites_count"`
+	ReblogsCount    int       `json:"reblogs_count"`
+	RepliesCount    int       `json:"replies_count"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (s *Status) Adapt() *domain.Post {
+	origin := s
+	if s.Reblog != nil {
+		origin = s.Reblog
+	}
+
+	return &domain.Post{
+		ID:     s.ID,
+		Driver: "mastodon",
+		User:   s.Account.Adapt(),
+		Text:   s.joinText(),
+		URL:    origin.URL,
+		Media:  origin.adaptMedia(),
+		Tags:   origin.adaptTags(),
+		Metrics: domain.Metrics{
+			Likes: origin.FavouritesCount, Reposts: origin.ReblogsCount, Replies: origin.RepliesCount,
+		},
+		ParentID:  s.InReplyToID,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func (s *Status) adaptMedia() []*domain.Media {
+	adapteds := make([]*domain.Media, len(s.MediaAttachments))
+	for i, m := range s.MediaAttachments {
+		adapteds[i] = &domain.Media{
+			Type: m.Type, URL: m.URL,
+		}
+	}
+
+	return adapteds
+}
+
+func (s *Status) adaptTags() []string {
+	adapteds := make([]string, len(s.Tags))
+	for i, t := range s.Tags {
+		adapteds[i] = t.Name
+	}
+
+	return adapteds
+}
+
+func (s *Status) joinText() string {
+	if s.Reblog != nil {
+		return fmt.Sprintf("RT @%s: %s", s.Reblog.Account.Acct, s.Reblog.joinText())
+	}
+
+	var b strings.Builder
+	if s.SpoilerText != "" {
+		fmt.Fprintf(&b, "CW: %s\n", s.SpoilerText)
+	}
+	b.WriteString(markup.StripHTML(s.Content))
+
+	return b.String()
+}
+
+type App struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+type Account struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	Acct        string `json:"acct"`
+	DisplayName string `json:"display_name"`
+}
+
+func (a *Account) Adapt() *domain.User {
+	name := a.DisplayName
+	if name == "" {
+		name = a.Username
+	}
+
+	return &domain.User{
+		ID:       a.ID,
+		Name:     name,
+		Username: a.Acct,
+	}
+}
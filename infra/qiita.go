@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -15,11 +14,13 @@ import (
 	"github.com/tomocy/smoothie/infra/qiita"
 )
 
+var qiitaInterval = pollInterval{def: 5 * time.Minute, min: time.Minute}
+
 type Qiita struct{}
 
-func (q *Qiita) StreamPosts(ctx context.Context, args []string) (<-chan domain.Posts, <-chan error) {
+func (q *Qiita) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
 	parsed := q.parseArgs(args)
-	isCh, errCh := q.streamItems(ctx, parsed.tag, nil)
+	isCh, errCh := q.streamItems(ctx, qiitaInterval.of(interval), parsed.tag, nil)
 	ch := make(chan domain.Posts)
 	go func() {
 		defer close(ch)
@@ -32,7 +33,7 @@ func (q *Qiita) StreamPosts(ctx context.Context, args []string) (<-chan domain.P
 	return ch, errCh
 }
 
-func (q *Qiita) streamItems(ctx context.Context, tag string, params url.Values) (<-chan qiita.Items, <-chan error) {
+func (q *Qiita) streamItems(ctx context.Context, interval time.Duration, tag string, params url.Values) (<-chan qiita.Items, <-chan error) {
 	isCh, errCh := make(chan qiita.Items), make(chan error)
 	go func() {
 		defer func() {
@@ -40,21 +41,20 @@ func (q *Qiita) streamItems(ctx context.Context, tag string, params url.Values)
 			close(errCh)
 		}()
 
-		lastCreatedAt := q.fetchAndSendItems(tag, params, isCh, errCh)
+		lastCreatedAt := q.fetchAndSendItems(ctx, tag, params, isCh, errCh)
 		for {
 			select {
 			case <-ctx.Done():
 				errCh <- ctx.Err()
 				return
-			case <-time.After(time.Second):
+			case <-time.After(interval):
 				if !lastCreatedAt.IsZero() {
 					if params == nil {
 						params = make(url.Values)
 					}
-					params.Add("query", fmt.Sprintf("created:>%s", lastCreatedAt))
-					log.Println(params.Get("query"))
+					params.Set("query", fmt.Sprintf("created:>=%s", lastCreatedAt.Format("2006-01-02")))
 				}
-				if createdAt := q.fetchAndSendItems(tag, params, isCh, errCh); !createdAt.IsZero() {
+				if createdAt := q.fetchAndSendItems(ctx, tag, params, isCh, errCh); !createdAt.IsZero() {
 					lastCreatedAt = createdAt
 				}
 			}
@@ -65,10 +65,10 @@ func (q *Qiita) streamItems(ctx context.Context, tag string, params url.Values)
 }
 
 func (q *Qiita) fetchAndSendItems(
-	tag string, params url.Values,
+	ctx context.Context, tag string, params url.Values,
 	isCh chan<- qiita.Items, errCh chan<- error,
 ) time.Time {
-	is, err := q.fetchItems(tag, params)
+	is, err := q.fetchItems(ctx, tag, params)
 	if err != nil {
 		errCh <- err
 		return time.Time{}
@@ -83,9 +83,9 @@ func (q *Qiita) fetchAndSendItems(
 	return lastCreatedAt
 }
 
-func (q *Qiita) FetchPosts(args []string) (domain.Posts, error) {
+func (q *Qiita) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
 	parsed := q.parseArgs(args)
-	is, err := q.fetchItems(parsed.tag, nil)
+	is, err := q.fetchItems(ctx, parsed.tag, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch posts: %s", err)
 	}
@@ -93,6 +93,32 @@ func (q *Qiita) FetchPosts(args []string) (domain.Posts, error) {
 	return is.Adapt(), nil
 }
 
+func (q *Qiita) SearchPosts(ctx context.Context, args []string, query string) (domain.Posts, error) {
+	parsed := q.parseArgs(args)
+	if parsed.tag != "" {
+		query = fmt.Sprintf("%s tag:%s", query, parsed.tag)
+	}
+	is, err := q.searchItems(ctx, url.Values{
+		"query": []string{query},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search posts: %s", err)
+	}
+
+	return is.Adapt(), nil
+}
+
+func (q *Qiita) searchItems(ctx context.Context, params url.Values) (qiita.Items, error) {
+	var is qiita.Items
+	if err := q.do(ctx, req{
+		method: http.MethodGet, url: q.endpoint("items"), params: params,
+	}, &is); err != nil {
+		return nil, err
+	}
+
+	return is, nil
+}
+
 func (q *Qiita) parseArgs(args []string) qiitaArgs {
 	var parsed qiitaArgs
 	parsed.parse(args)
@@ -100,9 +126,9 @@ func (q *Qiita) parseArgs(args []string) qiitaArgs {
 	return parsed
 }
 
-func (q *Qiita) fetchItems(tag string, params url.Values) (qiita.Items, error) {
+func (q *Qiita) fetchItems(ctx context.Context, tag string, params url.Values) (qiita.Items, error) {
 	var is qiita.Items
-	if err := q.do(req{
+	if err := q.do(ctx, req{
 		method: http.MethodGet, url: q.endpoint("tags", tag, "items"), params: params,
 	}, &is); err != nil {
 		return nil, err
@@ -111,8 +137,8 @@ func (q *Qiita) fetchItems(tag string, params url.Values) (qiita.Items, error) {
 	return is, nil
 }
 
-func (q *Qiita) do(r req, dst interface{}) error {
-	resp, err := r.do()
+func (q *Qiita) do(ctx context.Context, r req, dst interface{}) error {
+	resp, err := r.do(ctx)
 	if err != nil {
 		return err
 	}
@@ -1,7 +1,6 @@
 package qiita
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/tomocy/smoothie/domain"
@@ -19,23 +18,44 @@ func (is Items) Adapt() domain.Posts {
 }
 
 type Item struct {
-	ID        string    `json:"id"`
-	User      *User     `json:"user"`
-	Title     string    `json:"title"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"created_at"`
+	ID    string `json:"id"`
+	User  *User  `json:"user"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+	Tags  []*struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	LikesCount    int       `json:"likes_count"`
+	CommentsCount int       `json:"comments_count"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 func (i *Item) Adapt() *domain.Post {
 	return &domain.Post{
-		ID:        i.ID,
-		Driver:    "qiita",
-		User:      i.User.Adapt(),
-		Text:      fmt.Sprintf("%s\n\n%s", i.Title, i.Body),
+		ID:     i.ID,
+		Driver: "qiita",
+		User:   i.User.Adapt(),
+		Title:  i.Title,
+		Text:   i.Body,
+		URL:    i.URL,
+		Tags:   i.adaptTags(),
+		Metrics: domain.Metrics{
+			Likes: i.LikesCount, Replies: i.CommentsCount,
+		},
 		CreatedAt: i.CreatedAt,
 	}
 }
 
+func (i *Item) adaptTags() []string {
+	adapteds := make([]string, len(i.Tags))
+	for j, t := range i.Tags {
+		adapteds[j] = t.Name
+	}
+
+	return adapteds
+}
+
 type User struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
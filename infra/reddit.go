@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -27,7 +28,7 @@ func NewReddit(id, secret string, presenter authURLPresenter) *Reddit {
 					AuthStyle: oauth2.AuthStyleInHeader,
 				},
 				Scopes: []string{
-					"read", "identity", "mysubreddits",
+					"read", "identity", "mysubreddits", "submit", "vote",
 				},
 			},
 		},
@@ -35,13 +36,15 @@ func NewReddit(id, secret string, presenter authURLPresenter) *Reddit {
 	}
 }
 
+var redditInterval = pollInterval{def: 2 * time.Minute, min: 30 * time.Second}
+
 type Reddit struct {
 	oauth     oauth2Manager
 	presenter authURLPresenter
 }
 
-func (r *Reddit) StreamPosts(ctx context.Context, args []string) (<-chan domain.Posts, <-chan error) {
-	psCh, errCh := r.streamPosts(ctx, r.endpoint("/new"), nil)
+func (r *Reddit) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
+	psCh, errCh := r.streamPosts(ctx, redditInterval.of(interval), r.endpoint("/new"), nil)
 	ch := make(chan domain.Posts)
 	go func() {
 		defer close(ch)
@@ -53,7 +56,7 @@ func (r *Reddit) StreamPosts(ctx context.Context, args []string) (<-chan domain.
 	return ch, errCh
 }
 
-func (r *Reddit) streamPosts(ctx context.Context, dst string, params url.Values) (<-chan *reddit.Posts, <-chan error) {
+func (r *Reddit) streamPosts(ctx context.Context, interval time.Duration, dst string, params url.Values) (<-chan *reddit.Posts, <-chan error) {
 	psCh, errCh := make(chan *reddit.Posts), make(chan error)
 	go func() {
 		defer func() {
@@ -61,20 +64,20 @@ func (r *Reddit) streamPosts(ctx context.Context, dst string, params url.Values)
 			close(errCh)
 		}()
 
-		lastID := r.fetchAndSendPosts(dst, params, psCh, errCh)
+		lastID := r.fetchAndSendPosts(ctx, dst, params, psCh, errCh)
 		for {
 			select {
 			case <-ctx.Done():
 				errCh <- ctx.Err()
 				return
-			case <-time.After(2 * time.Minute):
+			case <-time.After(interval):
 				if lastID != "" {
 					if params == nil {
 						params = make(url.Values)
 					}
 					params.Set("before", lastID)
 				}
-				if id := r.fetchAndSendPosts(dst, params, psCh, errCh); id != "" {
+				if id := r.fetchAndSendPosts(ctx, dst, params, psCh, errCh); id != "" {
 					lastID = id
 				}
 			}
@@ -84,8 +87,8 @@ func (r *Reddit) streamPosts(ctx context.Context, dst string, params url.Values)
 	return psCh, errCh
 }
 
-func (r *Reddit) fetchAndSendPosts(dst string, params url.Values, psCh chan<- *reddit.Posts, errCh chan<- error) string {
-	ps, err := r.fetchPosts(dst, params)
+func (r *Reddit) fetchAndSendPosts(ctx context.Context, dst string, params url.Values, psCh chan<- *reddit.Posts, errCh chan<- error) string {
+	ps, err := r.fetchPosts(ctx, dst, params)
 	if err != nil {
 		return ""
 	}
@@ -99,8 +102,8 @@ func (r *Reddit) fetchAndSendPosts(dst string, params url.Values, psCh chan<- *r
 	return lastID
 }
 
-func (r *Reddit) FetchPosts(args []string) (domain.Posts, error) {
-	ps, err := r.fetchPosts(r.endpoint("/new"), nil)
+func (r *Reddit) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	ps, err := r.fetchPosts(ctx, r.endpoint("/new"), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +111,106 @@ func (r *Reddit) FetchPosts(args []string) (domain.Posts, error) {
 	return ps.Adapt(), nil
 }
 
-func (r *Reddit) fetchPosts(dst string, params url.Values) (*reddit.Posts, error) {
+func (r *Reddit) SearchPosts(ctx context.Context, args []string, q string) (domain.Posts, error) {
+	ps, err := r.fetchPosts(ctx, r.endpoint("/search"), url.Values{
+		"q": []string{q}, "sort": []string{"new"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ps.Adapt(), nil
+}
+
+func (r *Reddit) CreatePost(ctx context.Context, args []string, text string) (*domain.Post, error) {
+	if len(args) <= 0 || args[0] == "" {
+		return nil, errors.New("subreddit should be specified")
+	}
+
+	title, body := splitTitleAndBody(text)
+	submitted, err := r.submit(ctx, r.endpoint("/api/submit"), url.Values{
+		"sr": []string{args[0]}, "kind": []string{"self"}, "title": []string{title}, "text": []string{body},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create post: %s", err)
+	}
+
+	return &domain.Post{
+		ID: submitted.JSON.Data.Name, Driver: "reddit",
+		Channel: "r/" + args[0], Title: title, Text: body, URL: submitted.JSON.Data.URL,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func (r *Reddit) Reply(ctx context.Context, args []string, id, text string) (*domain.Post, error) {
+	submitted, err := r.submit(ctx, r.endpoint("/api/comment"), url.Values{
+		"thing_id": []string{id}, "text": []string{text},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reply: %s", err)
+	}
+	thing := submitted.Thing()
+	if thing == nil {
+		return nil, errors.New("failed to reply: no comment is returned")
+	}
+
+	return thing.Adapt(), nil
+}
+
+func (r *Reddit) React(ctx context.Context, args []string, id string, reaction domain.Reaction) error {
+	var dir string
+	switch reaction {
+	case domain.ReactionLike, domain.ReactionUpvote:
+		dir = "1"
+	case domain.ReactionDownvote:
+		dir = "-1"
+	default:
+		return &domain.UnsupportedReactionError{Reaction: reaction}
+	}
+
+	tok, err := r.retreiveAuthorization()
+	if err != nil {
+		return err
+	}
+
+	var voted struct{}
+	if err := r.do(ctx, oauth2Req{
+		tok: tok,
+		req: req{method: http.MethodPost, url: r.endpoint("/api/vote"), params: url.Values{
+			"id": []string{id}, "dir": []string{dir},
+		}},
+	}, &voted); err != nil {
+		return fmt.Errorf("failed to react: %s", err)
+	}
+
+	return r.saveAccessToken(tok)
+}
+
+func (r *Reddit) submit(ctx context.Context, dst string, params url.Values) (*reddit.Submitted, error) {
+	tok, err := r.retreiveAuthorization()
+	if err != nil {
+		return nil, err
+	}
+
+	params.Set("api_type", "json")
+	var submitted *reddit.Submitted
+	if err := r.do(ctx, oauth2Req{
+		tok: tok,
+		req: req{method: http.MethodPost, url: dst, params: params},
+	}, &submitted); err != nil {
+		return nil, err
+	}
+	if err := submitted.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.saveAccessToken(tok); err != nil {
+		return nil, err
+	}
+
+	return submitted, nil
+}
+
+func (r *Reddit) fetchPosts(ctx context.Context, dst string, params url.Values) (*reddit.Posts, error) {
 	tok, err := r.retreiveAuthorization()
 	if err != nil {
 		return nil, err
@@ -116,7 +218,7 @@ func (r *Reddit) fetchPosts(dst string, params url.Values) (*reddit.Posts, error
 
 	assured := r.assureDefaultParams(params)
 	var ps *reddit.Posts
-	if err := r.do(oauth2Req{
+	if err := r.do(ctx, oauth2Req{
 		tok: tok,
 		req: req{method: http.MethodGet, url: dst, params: assured},
 	}, &ps); err != nil {
@@ -129,8 +231,17 @@ func (r *Reddit) fetchPosts(dst string, params url.Values) (*reddit.Posts, error
 	return ps, nil
 }
 
+func (r *Reddit) Authorize(ctx context.Context, args []string) error {
+	tok, err := r.retreiveAuthorization()
+	if err != nil {
+		return err
+	}
+
+	return r.saveAccessToken(tok)
+}
+
 func (r *Reddit) retreiveAuthorization() (*oauth2.Token, error) {
-	if cnf, err := r.loadConfig(); err == nil && !cnf.isZero() {
+	if cnf, err := r.loadConfig(); err == nil && !cnf.isZero() && cnf.covers(r.oauth.cnf.Scopes) {
 		return cnf.AccessToken, nil
 	}
 
@@ -154,7 +265,7 @@ func (r *Reddit) authCodeURL() string {
 }
 
 func (r *Reddit) handleAuthorizationRedirect() (*oauth2.Token, error) {
-	return r.oauth.handleRedirect(r.contextWithUserAgent(), nil, "/smoothie/reddit/authorization")
+	return r.oauth.handleRedirect(r.contextWithUserAgent(context.Background()), nil, "/smoothie/reddit/authorization")
 }
 
 func (r *Reddit) assureDefaultParams(params url.Values) url.Values {
@@ -167,8 +278,8 @@ func (r *Reddit) assureDefaultParams(params url.Values) url.Values {
 	return assured
 }
 
-func (r *Reddit) do(req oauth2Req, dst interface{}) error {
-	resp, err := req.do(r.contextWithUserAgent(), r.oauth.cnf)
+func (r *Reddit) do(ctx context.Context, req oauth2Req, dst interface{}) error {
+	resp, err := req.do(r.contextWithUserAgent(ctx), r.oauth.cnf)
 	if err != nil {
 		return err
 	}
@@ -181,8 +292,8 @@ func (r *Reddit) do(req oauth2Req, dst interface{}) error {
 	return json.NewDecoder(resp.Body).Decode(dst)
 }
 
-func (r *Reddit) contextWithUserAgent() context.Context {
-	return context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
+func (r *Reddit) contextWithUserAgent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, &http.Client{
 		Transport: new(withUserAgent),
 	})
 }
@@ -192,7 +303,7 @@ func (r *Reddit) saveAccessToken(tok *oauth2.Token) error {
 	if err != nil {
 		return err
 	}
-	loaded.AccessToken = tok
+	loaded.AccessToken, loaded.Scopes = tok, r.oauth.cnf.Scopes
 
 	return r.saveConfig(loaded)
 }
@@ -27,12 +27,55 @@ func (ps Posts) Adapt() []*domain.Post {
 	return adapteds
 }
 
+type Submitted struct {
+	JSON struct {
+		Errors [][]interface{} `json:"errors"`
+		Data   struct {
+			Things []*struct {
+				Data Post `json:"data"`
+			} `json:"things"`
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"data"`
+	} `json:"json"`
+}
+
+func (s *Submitted) Err() error {
+	if len(s.JSON.Errors) <= 0 {
+		return nil
+	}
+
+	msgs := make([]string, len(s.JSON.Errors))
+	for i, e := range s.JSON.Errors {
+		msgs[i] = fmt.Sprint(e...)
+	}
+
+	return errors.New(strings.Join(msgs, ", "))
+}
+
+func (s *Submitted) Thing() *Post {
+	if len(s.JSON.Data.Things) <= 0 {
+		return nil
+	}
+
+	return &s.JSON.Data.Things[0].Data
+}
+
 type Post struct {
 	Name                  string        `json:"name"`
 	SubredditNamePrefixed string        `json:"subreddit_name_prefixed"`
 	Author                string        `json:"author"`
 	Title                 string        `json:"title"`
 	SelfText              string        `json:"selftext"`
+	Body                  string        `json:"body"`
+	ParentID              string        `json:"parent_id"`
+	URL                   string        `json:"url"`
+	IsSelf                bool          `json:"is_self"`
+	Permalink             string        `json:"permalink"`
+	PostHint              string        `json:"post_hint"`
+	LinkFlairText         string        `json:"link_flair_text"`
+	Score                 int           `json:"score"`
+	NumComments           int           `json:"num_comments"`
 	CreatedUTC            unixTimestamp `json:"created_utc"`
 }
 
@@ -43,19 +86,59 @@ func (p *Post) Adapt() *domain.Post {
 		User: &domain.User{
 			Name: p.Author,
 		},
-		Text:      p.joinText(),
+		Channel: p.SubredditNamePrefixed,
+		Title:   p.Title,
+		Text:    p.joinText(),
+		URL:     fmt.Sprintf("https://www.reddit.com%s", p.Permalink),
+		Link:    p.adaptLink(),
+		Media:   p.adaptMedia(),
+		Tags:    p.adaptTags(),
+		Metrics: domain.Metrics{
+			Replies: p.NumComments, Score: p.Score,
+		},
+		ParentID:  p.ParentID,
 		CreatedAt: time.Time(p.CreatedUTC),
 	}
 }
 
 func (p *Post) joinText() string {
-	var b strings.Builder
-	fmt.Fprintf(&b, "%s %s", p.SubredditNamePrefixed, p.Title)
 	if p.SelfText != "" {
-		fmt.Fprintf(&b, "\n\n%s", p.SelfText)
+		return p.SelfText
+	}
+	if p.Body != "" {
+		return p.Body
+	}
+	if p.PostHint == "image" {
+		return ""
+	}
+
+	return p.URL
+}
+
+func (p *Post) adaptLink() string {
+	if p.IsSelf {
+		return ""
+	}
+
+	return p.URL
+}
+
+func (p *Post) adaptMedia() []*domain.Media {
+	if p.PostHint != "image" {
+		return nil
+	}
+
+	return []*domain.Media{
+		{Type: "image", URL: p.URL},
+	}
+}
+
+func (p *Post) adaptTags() []string {
+	if p.LinkFlairText == "" {
+		return nil
 	}
 
-	return b.String()
+	return []string{p.LinkFlairText}
 }
 
 type unixTimestamp time.Time
@@ -32,13 +32,15 @@ func NewTumblr(id, secret string, presenter authURLPresenter) *Tumblr {
 	}
 }
 
+var tumblrInterval = pollInterval{def: 5 * time.Minute, min: time.Minute}
+
 type Tumblr struct {
 	oauth     oauthManager
 	presenter authURLPresenter
 }
 
-func (t *Tumblr) StreamPosts(ctx context.Context, args []string) (<-chan domain.Posts, <-chan error) {
-	psCh, errCh := t.streamPosts(ctx, t.endpoint("/user/dashboard"), nil)
+func (t *Tumblr) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
+	psCh, errCh := t.streamPosts(ctx, tumblrInterval.of(interval), t.endpoint("/user/dashboard"), nil)
 	ch := make(chan domain.Posts)
 	go func() {
 		defer close(ch)
@@ -50,7 +52,7 @@ func (t *Tumblr) StreamPosts(ctx context.Context, args []string) (<-chan domain.
 	return ch, errCh
 }
 
-func (t *Tumblr) streamPosts(ctx context.Context, dst string, params url.Values) (<-chan tumblr.Posts, <-chan error) {
+func (t *Tumblr) streamPosts(ctx context.Context, interval time.Duration, dst string, params url.Values) (<-chan tumblr.Posts, <-chan error) {
 	psCh, errCh := make(chan tumblr.Posts), make(chan error)
 	go func() {
 		defer func() {
@@ -58,20 +60,20 @@ func (t *Tumblr) streamPosts(ctx context.Context, dst string, params url.Values)
 			close(errCh)
 		}()
 
-		lastID := t.fetchAndSendPosts(dst, params, psCh, errCh)
+		lastID := t.fetchAndSendPosts(ctx, dst, params, psCh, errCh)
 		for {
 			select {
 			case <-ctx.Done():
 				errCh <- ctx.Err()
 				return
-			case <-time.After(5 * time.Minute):
+			case <-time.After(interval):
 				if lastID != "" {
 					if params == nil {
 						params = make(url.Values)
 					}
 					params.Set("since_id", lastID)
 				}
-				if id := t.fetchAndSendPosts(dst, params, psCh, errCh); id != "" {
+				if id := t.fetchAndSendPosts(ctx, dst, params, psCh, errCh); id != "" {
 					lastID = id
 				}
 			}
@@ -81,8 +83,8 @@ func (t *Tumblr) streamPosts(ctx context.Context, dst string, params url.Values)
 	return psCh, errCh
 }
 
-func (t *Tumblr) fetchAndSendPosts(dst string, params url.Values, psCh chan<- tumblr.Posts, errCh chan<- error) string {
-	ps, err := t.fetchPosts(dst, params)
+func (t *Tumblr) fetchAndSendPosts(ctx context.Context, dst string, params url.Values, psCh chan<- tumblr.Posts, errCh chan<- error) string {
+	ps, err := t.fetchPosts(ctx, dst, params)
 	if err != nil {
 		errCh <- err
 		return ""
@@ -95,8 +97,8 @@ func (t *Tumblr) fetchAndSendPosts(dst string, params url.Values, psCh chan<- tu
 	return fmt.Sprintf("%d", ps[0].ID)
 }
 
-func (t *Tumblr) FetchPosts(args []string) (domain.Posts, error) {
-	ps, err := t.fetchPosts(t.endpoint("/user/dashboard"), nil)
+func (t *Tumblr) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	ps, err := t.fetchPosts(ctx, t.endpoint("/user/dashboard"), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -104,14 +106,14 @@ func (t *Tumblr) FetchPosts(args []string) (domain.Posts, error) {
 	return ps.Adapt(), nil
 }
 
-func (t *Tumblr) fetchPosts(dst string, params url.Values) (tumblr.Posts, error) {
+func (t *Tumblr) fetchPosts(ctx context.Context, dst string, params url.Values) (tumblr.Posts, error) {
 	cred, err := t.retreiveAuthorization()
 	if err != nil {
 		return nil, err
 	}
 
 	var resp *tumblr.Resp
-	if err := t.do(oauthReq{
+	if err := t.do(ctx, oauthReq{
 		cred: cred,
 		req:  req{method: http.MethodGet, url: dst, params: params},
 	}, &resp); err != nil {
@@ -124,6 +126,15 @@ func (t *Tumblr) fetchPosts(dst string, params url.Values) (tumblr.Posts, error)
 	return resp.Resp.Posts, nil
 }
 
+func (t *Tumblr) Authorize(ctx context.Context, args []string) error {
+	cred, err := t.retreiveAuthorization()
+	if err != nil {
+		return err
+	}
+
+	return t.saveAccessToken(cred)
+}
+
 func (t *Tumblr) retreiveAuthorization() (*oauth.Credentials, error) {
 	if cnf, err := t.loadConfig(); err == nil && !cnf.isZero() {
 		return cnf.AccessCredentials, nil
@@ -151,8 +162,8 @@ func (t *Tumblr) handleAuthorizationRedirect() (*oauth.Credentials, error) {
 	return t.oauth.handleRedirect(context.Background(), "/smoothie/tumblr/authorization")
 }
 
-func (t *Tumblr) do(r oauthReq, dst interface{}) error {
-	resp, err := r.do(t.oauth.client)
+func (t *Tumblr) do(ctx context.Context, r oauthReq, dst interface{}) error {
+	resp, err := r.do(ctx, t.oauth.client)
 	if err != nil {
 		return err
 	}
@@ -2,7 +2,6 @@ package tumblr
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/tomocy/smoothie/domain"
@@ -26,11 +25,19 @@ func (ps Posts) Adapt() domain.Posts {
 }
 
 type Post struct {
-	ID       int      `json:"id"`
-	BlogName string   `json:"blog_name"`
-	Summary  string   `json:"summary"`
-	Tags     []string `json:"tags"`
-	Date     date     `json:"date"`
+	ID        int      `json:"id"`
+	BlogName  string   `json:"blog_name"`
+	PostURL   string   `json:"post_url"`
+	Title     string   `json:"title"`
+	Summary   string   `json:"summary"`
+	Tags      []string `json:"tags"`
+	NoteCount int      `json:"note_count"`
+	Photos    []*struct {
+		OriginalSize struct {
+			URL string `json:"url"`
+		} `json:"original_size"`
+	} `json:"photos"`
+	Date date `json:"date"`
 }
 
 func (p *Post) Adapt() *domain.Post {
@@ -40,13 +47,28 @@ func (p *Post) Adapt() *domain.Post {
 		User: &domain.User{
 			Name: p.BlogName,
 		},
-		Text:      p.joinText(),
+		Channel: p.BlogName,
+		Title:   p.Title,
+		Text:    p.Summary,
+		URL:     p.PostURL,
+		Media:   p.adaptMedia(),
+		Tags:    p.Tags,
+		Metrics: domain.Metrics{
+			Likes: p.NoteCount,
+		},
 		CreatedAt: time.Time(p.Date),
 	}
 }
 
-func (p *Post) joinText() string {
-	return fmt.Sprintf("%s\n%s", p.Summary, strings.Join(p.Tags, " "))
+func (p *Post) adaptMedia() []*domain.Media {
+	adapteds := make([]*domain.Media, len(p.Photos))
+	for i, photo := range p.Photos {
+		adapteds[i] = &domain.Media{
+			Type: "photo", URL: photo.OriginalSize.URL,
+		}
+	}
+
+	return adapteds
 }
 
 type date time.Time
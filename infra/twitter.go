@@ -32,13 +32,15 @@ func NewTwitter(id, secret string, presenter authURLPresenter) *Twitter {
 	}
 }
 
+var twitterInterval = pollInterval{def: 4 * time.Minute, min: time.Minute}
+
 type Twitter struct {
 	oauth     oauthManager
 	presenter authURLPresenter
 }
 
-func (t *Twitter) StreamPosts(ctx context.Context, args []string) (<-chan domain.Posts, <-chan error) {
-	tsCh, errCh := t.streamTweets(ctx, nil)
+func (t *Twitter) StreamPosts(ctx context.Context, args []string, interval time.Duration) (<-chan domain.Posts, <-chan error) {
+	tsCh, errCh := t.streamTweets(ctx, twitterInterval.of(interval), nil)
 	ch := make(chan domain.Posts)
 	go func() {
 		defer close(ch)
@@ -50,7 +52,7 @@ func (t *Twitter) StreamPosts(ctx context.Context, args []string) (<-chan domain
 	return ch, errCh
 }
 
-func (t *Twitter) streamTweets(ctx context.Context, params url.Values) (<-chan twitter.Tweets, <-chan error) {
+func (t *Twitter) streamTweets(ctx context.Context, interval time.Duration, params url.Values) (<-chan twitter.Tweets, <-chan error) {
 	tsCh, errCh := make(chan twitter.Tweets), make(chan error)
 	go func() {
 		defer func() {
@@ -64,7 +66,7 @@ func (t *Twitter) streamTweets(ctx context.Context, params url.Values) (<-chan t
 			case <-ctx.Done():
 				errCh <- ctx.Err()
 				return
-			case <-time.After(4 * time.Minute):
+			case <-time.After(interval):
 				if lastID != "" {
 					if params == nil {
 						params = make(url.Values)
@@ -85,7 +87,7 @@ func (t *Twitter) fetchAndSendTweets(
 	ctx context.Context, params url.Values,
 	tsCh chan<- twitter.Tweets, errCh chan<- error,
 ) string {
-	ts, err := t.fetchTweets(params)
+	ts, err := t.fetchTweets(ctx, params)
 	if err != nil {
 		select {
 		case <-ctx.Done():
@@ -106,8 +108,8 @@ func (t *Twitter) fetchAndSendTweets(
 	return ts[0].ID
 }
 
-func (t *Twitter) FetchPosts(args []string) (domain.Posts, error) {
-	ts, err := t.fetchTweets(nil)
+func (t *Twitter) FetchPosts(ctx context.Context, args []string) (domain.Posts, error) {
+	ts, err := t.fetchTweets(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch posts: %s", err)
 	}
@@ -115,25 +117,120 @@ func (t *Twitter) FetchPosts(args []string) (domain.Posts, error) {
 	return ts.Adapt(), nil
 }
 
-func (t *Twitter) fetchTweets(params url.Values) (twitter.Tweets, error) {
-	cred, err := t.retreiveAuthorization()
-	if err != nil {
+func (t *Twitter) fetchTweets(ctx context.Context, params url.Values) (twitter.Tweets, error) {
+	var ts twitter.Tweets
+	if err := t.request(ctx, req{
+		method: http.MethodGet, url: t.endpoint("/statuses/home_timeline.json"), params: t.assureDefaultParams(params),
+	}, &ts); err != nil {
 		return nil, err
 	}
 
+	return ts, nil
+}
+
+func (t *Twitter) SearchPosts(ctx context.Context, args []string, q string) (domain.Posts, error) {
+	ts, err := t.searchTweets(ctx, url.Values{
+		"q": []string{q}, "result_type": []string{"recent"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search posts: %s", err)
+	}
+
+	return ts.Adapt(), nil
+}
+
+func (t *Twitter) searchTweets(ctx context.Context, params url.Values) (twitter.Tweets, error) {
 	assured := t.assureDefaultParams(params)
-	var ts twitter.Tweets
-	if err := t.do(oauthReq{
-		cred: cred,
-		req:  req{method: http.MethodGet, url: t.endpoint("/statuses/home_timeline.json"), params: assured},
-	}, &ts); err != nil {
+	assured.Set("count", "100")
+	var searched twitter.SearchedTweets
+	if err := t.request(ctx, req{
+		method: http.MethodGet, url: t.endpoint("/search/tweets.json"), params: assured,
+	}, &searched); err != nil {
 		return nil, err
 	}
-	if err := t.saveAccessCredentials(cred); err != nil {
+
+	return searched.Statuses, nil
+}
+
+func (t *Twitter) CreatePost(ctx context.Context, args []string, text string) (*domain.Post, error) {
+	tw, err := t.updateStatus(ctx, url.Values{
+		"status": []string{text},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create post: %s", err)
+	}
+
+	return tw.Adapt(), nil
+}
+
+func (t *Twitter) Reply(ctx context.Context, args []string, id, text string) (*domain.Post, error) {
+	tw, err := t.updateStatus(ctx, url.Values{
+		"status": []string{text}, "in_reply_to_status_id": []string{id}, "auto_populate_reply_metadata": []string{"true"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reply: %s", err)
+	}
+
+	return tw.Adapt(), nil
+}
+
+func (t *Twitter) React(ctx context.Context, args []string, id string, r domain.Reaction) error {
+	var dst string
+	switch r {
+	case domain.ReactionLike:
+		dst = t.endpoint("/favorites/create.json")
+	case domain.ReactionBoost:
+		dst = t.endpoint("/statuses/retweet", id+".json")
+	default:
+		return &domain.UnsupportedReactionError{Reaction: r}
+	}
+
+	var tw *twitter.Tweet
+	if err := t.request(ctx, req{
+		method: http.MethodPost, url: dst, params: url.Values{
+			"id": []string{id},
+		},
+	}, &tw); err != nil {
+		return fmt.Errorf("failed to react: %s", err)
+	}
+
+	return nil
+}
+
+func (t *Twitter) updateStatus(ctx context.Context, params url.Values) (*twitter.Tweet, error) {
+	params.Set("tweet_mode", "extended")
+	var tw *twitter.Tweet
+	if err := t.request(ctx, req{
+		method: http.MethodPost, url: t.endpoint("/statuses/update.json"), params: params,
+	}, &tw); err != nil {
 		return nil, err
 	}
 
-	return ts, nil
+	return tw, nil
+}
+
+func (t *Twitter) request(ctx context.Context, r req, v interface{}) error {
+	cred, err := t.retreiveAuthorization()
+	if err != nil {
+		return err
+	}
+
+	if err := t.do(ctx, oauthReq{
+		cred: cred, req: r,
+	}, v); err != nil {
+		return err
+	}
+
+	return t.saveAccessCredentials(cred)
+}
+
+func (t *Twitter) Authorize(ctx context.Context, args []string) error {
+	cred, err := t.retreiveAuthorization()
+	if err != nil {
+		return err
+	}
+
+	return t.saveAccessCredentials(cred)
 }
 
 func (t *Twitter) retreiveAuthorization() (*oauth.Credentials, error) {
@@ -176,8 +273,8 @@ func (t *Twitter) assureDefaultParams(params url.Values) url.Values {
 	return assured
 }
 
-func (t *Twitter) do(r oauthReq, dst interface{}) error {
-	resp, err := r.do(t.oauth.client)
+func (t *Twitter) do(ctx context.Context, r oauthReq, dst interface{}) error {
+	resp, err := r.do(ctx, t.oauth.client)
 	if err != nil {
 		return err
 	}
@@ -1,11 +1,16 @@
 package twitter
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/tomocy/smoothie/domain"
 )
 
+type SearchedTweets struct {
+	Statuses Tweets `json:"statuses"`
+}
+
 type Tweets []*Tweet
 
 func (ts Tweets) Adapt() domain.Posts {
@@ -18,11 +23,16 @@ func (ts Tweets) Adapt() domain.Posts {
 }
 
 type Tweet struct {
-	ID        string `json:"id_str"`
-	User      *User  `json:"user"`
-	Text      string `json:"text"`
-	FullText  string `json:"full_text"`
-	CreatedAt date   `json:"created_at"`
+	ID                string   `json:"id_str"`
+	User              *User    `json:"user"`
+	Text              string   `json:"text"`
+	FullText          string   `json:"full_text"`
+	Entities          entities `json:"entities"`
+	ExtendedEntities  entities `json:"extended_entities"`
+	FavoriteCount     int      `json:"favorite_count"`
+	RetweetCount      int      `json:"retweet_count"`
+	InReplyToStatusID string   `json:"in_reply_to_status_id_str"`
+	CreatedAt         date     `json:"created_at"`
 }
 
 func (t *Tweet) Adapt() *domain.Post {
@@ -31,8 +41,61 @@ func (t *Tweet) Adapt() *domain.Post {
 		text = t.FullText
 	}
 	return &domain.Post{
-		ID: t.ID, Driver: "twitter", User: t.User.Adapt(), Text: text, CreatedAt: time.Time(t.CreatedAt),
+		ID: t.ID, Driver: "twitter", User: t.User.Adapt(), Text: text,
+		URL:   fmt.Sprintf("https://twitter.com/%s/status/%s", t.User.ScreenName, t.ID),
+		Link:  t.adaptLink(),
+		Media: t.adaptMedia(), Tags: t.adaptTags(),
+		Metrics: domain.Metrics{
+			Likes: t.FavoriteCount, Reposts: t.RetweetCount,
+		},
+		ParentID:  t.InReplyToStatusID,
+		CreatedAt: time.Time(t.CreatedAt),
+	}
+}
+
+func (t *Tweet) adaptLink() string {
+	if len(t.Entities.URLs) <= 0 {
+		return ""
+	}
+
+	return t.Entities.URLs[0].ExpandedURL
+}
+
+func (t *Tweet) adaptMedia() []*domain.Media {
+	ms := t.ExtendedEntities.Media
+	if len(ms) <= 0 {
+		ms = t.Entities.Media
+	}
+	adapteds := make([]*domain.Media, len(ms))
+	for i, m := range ms {
+		adapteds[i] = &domain.Media{
+			Type: m.Type, URL: m.MediaURLHTTPS,
+		}
 	}
+
+	return adapteds
+}
+
+func (t *Tweet) adaptTags() []string {
+	adapteds := make([]string, len(t.Entities.Hashtags))
+	for i, h := range t.Entities.Hashtags {
+		adapteds[i] = h.Text
+	}
+
+	return adapteds
+}
+
+type entities struct {
+	Hashtags []*struct {
+		Text string `json:"text"`
+	} `json:"hashtags"`
+	URLs []*struct {
+		ExpandedURL string `json:"expanded_url"`
+	} `json:"urls"`
+	Media []*struct {
+		Type          string `json:"type"`
+		MediaURLHTTPS string `json:"media_url_https"`
+	} `json:"media"`
 }
 
 type date time.Time